@@ -0,0 +1,153 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+/* ───────────── audio analysis ───────────── */
+
+// The audio callback only copies samples into tap; everything else runs on
+// the analyzer goroutine at the UI frame rate, so a slow frame can never
+// make the speaker underrun.
+
+const (
+	tapSize     = 1 << 15 // ~0.7s at 44.1kHz
+	tapMask     = tapSize - 1
+	scopeWindow = 1024 // samples the oscilloscope shows
+	vizInterval = 33 * time.Millisecond
+)
+
+// audioTap is a single-producer ring: the audio thread writes samples and
+// then publishes the new write position, the analyzer reads behind it.
+type audioTap struct {
+	buf [tapSize][2]float64
+	w   atomic.Uint64
+	sr  atomic.Uint64 // math.Float64bits of the current stream's rate
+}
+
+var tap audioTap
+
+func (t *audioTap) write(samples [][2]float64) {
+	w := t.w.Load()
+	for _, s := range samples {
+		t.buf[w&tapMask] = s
+		w++
+	}
+	t.w.Store(w)
+}
+
+type visualizerStreamer struct {
+	beep.Streamer
+}
+
+func newVisualizerStreamer(s beep.Streamer, sr beep.SampleRate) *visualizerStreamer {
+	tap.sr.Store(math.Float64bits(float64(sr)))
+	analyzerOnce.Do(func() { go runAnalyzer() })
+	return &visualizerStreamer{Streamer: s}
+}
+
+func (vs *visualizerStreamer) Stream(samples [][2]float64) (int, bool) {
+	n, ok := vs.Streamer.Stream(samples)
+	tap.write(samples[:n])
+	return n, ok
+}
+
+var (
+	analyzerOnce sync.Once
+
+	vizMu   sync.Mutex
+	vizSnap vizFrame
+	vizSeq  uint64
+)
+
+// runAnalyzer turns whatever the tap gained since the last tick into one
+// vizFrame. Buffers are rebuilt only when the sample rate or the column
+// count changes.
+func runAnalyzer() {
+	var (
+		r    uint64
+		sr   float64
+		cols int
+		spec *spectrum
+		beat *beatDetector
+		f    vizFrame
+	)
+	for range time.Tick(vizInterval) {
+		w := tap.w.Load()
+		if w == r {
+			continue
+		}
+		if w-r > tapSize/2 {
+			r = w - tapSize/2 // fell behind; drop the oldest
+		}
+		if s := math.Float64frombits(tap.sr.Load()); s != sr || spec == nil {
+			sr, cols = s, int(vizCols.Load())
+			spec = newSpectrum(sr, cols)
+			// keep the onset count running so the UI doesn't see a jump
+			var beats uint64
+			if beat != nil {
+				beats = beat.beats
+			}
+			beat = newBeatDetector(sr)
+			beat.beats = beats
+		}
+		if c := int(vizCols.Load()); c != cols {
+			cols = c
+			spec.resize(cols)
+		}
+
+		n := w - r
+		var sum [2]float64
+		for ; r < w; r++ {
+			s := tap.buf[r&tapMask]
+			spec.push(s)
+			beat.sample(s)
+			sum[0] += s[0] * s[0]
+			sum[1] += s[1] * s[1]
+		}
+		f.rms[0] = math.Sqrt(sum[0] / float64(n))
+		f.rms[1] = math.Sqrt(sum[1] / float64(n))
+
+		f.bands = resized(f.bands, cols)
+		spec.analyze(f.bands)
+		f.scope = resized(f.scope, cols*2)
+		for i := range f.scope {
+			f.scope[i] = tap.buf[(w-scopeWindow+uint64(i*scopeWindow/len(f.scope)))&tapMask]
+		}
+		f.bpm, f.beats = beat.bpm, beat.beats
+
+		vizMu.Lock()
+		vizSnap.bands = append(vizSnap.bands[:0], f.bands...)
+		vizSnap.scope = append(vizSnap.scope[:0], f.scope...)
+		vizSnap.rms, vizSnap.bpm, vizSnap.beats = f.rms, f.bpm, f.beats
+		vizSeq++
+		vizMu.Unlock()
+	}
+}
+
+// latestViz copies the newest frame into f, reusing its slices, if there
+// is one newer than *seq.
+func latestViz(f *vizFrame, seq *uint64) bool {
+	vizMu.Lock()
+	defer vizMu.Unlock()
+	if vizSeq == *seq {
+		return false
+	}
+	*seq = vizSeq
+	f.bands = append(f.bands[:0], vizSnap.bands...)
+	f.scope = append(f.scope[:0], vizSnap.scope...)
+	f.rms, f.bpm, f.beats = vizSnap.rms, vizSnap.bpm, vizSnap.beats
+	return true
+}
+
+func resized[T any](s []T, n int) []T {
+	if cap(s) < n {
+		return make([]T, n)
+	}
+	return s[:n]
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/faiface/beep"
+)
+
+// The streamer runs on the audio callback, where an allocation can stall
+// playback long enough to underrun. The analyzer isn't started here (that
+// goroutine may allocate and would be counted too).
+func newTestStreamer() (*visualizerStreamer, [][2]float64) {
+	return &visualizerStreamer{Streamer: beep.Silence(-1)}, make([][2]float64, 512)
+}
+
+func TestVisualizerStreamerNoAllocs(t *testing.T) {
+	vs, buf := newTestStreamer()
+	if n := testing.AllocsPerRun(1000, func() { vs.Stream(buf) }); n != 0 {
+		t.Errorf("Stream allocates %v times per call, want 0", n)
+	}
+}
+
+func BenchmarkVisualizerStreamer(b *testing.B) {
+	vs, buf := newTestStreamer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		vs.Stream(buf)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nfnt/resize"
+)
+
+/* ───────────── station logos ───────────── */
+
+// artImages holds logos that came from a PNG or JPEG. Unlike text logos
+// they are rendered fresh for each panel size, so full-screen stays sharp.
+var artImages = map[string]image.Image{}
+
+func isArtImage(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg":
+		return true
+	}
+	return false
+}
+
+// loadStationArt sets iconKey's logo from a .txt, .png or .jpg file. The
+// path is used as given; catalog entries resolve theirs first.
+func loadStationArt(iconKey, path string) error {
+	if !isArtImage(path) {
+		art, err := loadArtFile(path)
+		if err != nil {
+			return err
+		}
+		stationArt[iconKey] = art
+		delete(artImages, iconKey)
+		clear(scaledArtCache)
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("art: %w", err)
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("art: %s: %w", path, err)
+	}
+	artImages[iconKey] = img
+	delete(stationArt, iconKey)
+	clear(scaledArtCache)
+	return nil
+}
+
+// loadArtPacks picks up logos dropped into art/ in the config dir, named
+// by icon key (darksynth.png, mystation.txt). Packs may sit in their own
+// subfolders. Runs before the catalog, so an entry's own art still wins.
+func loadArtPacks() {
+	dir := filepath.Join(configDir(), "art")
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".txt" && !isArtImage(path) {
+			return nil
+		}
+		key := strings.ToLower(strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())))
+		if err := loadStationArt(key, path); err != nil {
+			logf("%v", err)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logf("art: %v", err)
+	}
+}
+
+// stationLogo returns iconKey's logo at w x h cells. Image logos go
+// through the luminance ramp at that size; text ones are resampled when
+// the panel isn't the default one.
+func stationLogo(iconKey string, w, h int) string {
+	if img, ok := artImages[iconKey]; ok {
+		key := fmt.Sprintf("img:%s/%dx%d", iconKey, w, h)
+		if s, ok := scaledArtCache[key]; ok {
+			return s
+		}
+		if len(scaledArtCache) > 32 {
+			clear(scaledArtCache)
+		}
+		s := imageArt(img, w, h)
+		scaledArtCache[key] = s
+		return s
+	}
+	art, ok := stationArt[iconKey]
+	if !ok {
+		art = asciiArt
+	}
+	if w == asciiArtWidth() && h == asciiArtHeight() {
+		return art
+	}
+	return scaledArt(iconKey, art, w, h)
+}
+
+// imageArt converts img to w x h ramp characters, the same way toASCII
+// draws DOOM but without color: the station gradient is laid over it
+// like any other logo. A logo on a light background is inverted so the
+// mark is what lights up; transparent pixels always stay background.
+func imageArt(img image.Image, w, h int) string {
+	rgba, _ := ensureRGBA(resize.Resize(uint(w), uint(h), img, resize.Bilinear))
+	lum := make([]float64, w*h)
+	alpha := make([]float64, w*h)
+	edge, edges := 0.0, 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			o := y*rgba.Stride + x*4
+			p := rgba.Pix[o : o+4 : o+4]
+			// Pix is premultiplied, so transparent reads as black
+			l := (float64(p[0])*3 + float64(p[1])*6 + float64(p[2])) / (255 * 10)
+			lum[y*w+x], alpha[y*w+x] = l, float64(p[3])/255
+			if x == 0 || y == 0 || x == w-1 || y == h-1 {
+				edge += l
+				edges++
+			}
+		}
+	}
+	invert := edges > 0 && edge/float64(edges) > 0.5
+
+	var sb strings.Builder
+	for y := 0; y < h; y++ {
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+		for x := 0; x < w; x++ {
+			l := lum[y*w+x]
+			if invert {
+				l = alpha[y*w+x] - l
+			}
+			sb.WriteByte(ramp[min(len(ramp)-1, max(0, int(l*float64(len(ramp)-1)+0.5)))])
+		}
+	}
+	return sb.String()
+}
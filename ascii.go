@@ -215,6 +215,19 @@ var EasterEgg = `
 ▓▓▓▓▓▓▓▓▓▓████████████████████████████████████▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓
 `
 
+// stationArt maps station icon keys to their logo art. Stations without
+// an entry use asciiArt.
+var stationArt = map[string]string{
+	"darksynth":   darkArt,
+	"chillsynth":  chillArt,
+	"datawave":    dataArt,
+	"ebsm":        EbsmArt,
+	"horrorsynth": horrorArt,
+	"spacesynth":  spaceArt,
+	"rekt":        rektArt,
+	"rektory":     RektoryArt,
+}
+
 // StationColors maps station icon keys to their color schemes
 var StationColors = map[string][]string{
 	"nrfm":        {"#ff366a", "#fb004b"}, // Pink to Dark Pink
@@ -244,15 +257,16 @@ func asciiArtHeight() int {
 
 // CreateGradientForStation creates a vertical gradient for the given station
 func CreateGradientForStation(iconKey string) func(int) string {
-	colors, exists := StationColors[iconKey]
-	if !exists {
-		// Default to the original pink-purple gradient
-		colors = []string{"#ff386f", "#7d3cff"}
-	}
+	return createGradient(iconKey, asciiArtHeight())
+}
+
+// createGradient spreads the station's gradient over height rows.
+func createGradient(iconKey string, height int) func(int) string {
+	colors := stationColors(iconKey)
 
 	topColor := colors[0]
 	bottomColor := colors[1]
-	height := asciiArtHeight()
+	height = max(2, height)
 
 	return func(y int) string {
 		// Parse hex colors
@@ -280,31 +294,17 @@ func parseHexColor(hex string) (int, int, int) {
 
 // RenderVisualizedASCII renders the ASCII art with visualization bars and gradient
 func RenderVisualizedASCII(barHeights []int, iconKey string) string {
-	//conditionally render ascii based on station
-	var art string
-	if iconKey == "chillsynth" {
-		art = chillArt
-	} else if iconKey == "darksynth" {
-		art = darkArt
-	} else if iconKey == "datawave" {
-		art = dataArt
-	} else if iconKey == "ebsm" {
-		art = EbsmArt
-	} else if iconKey == "horrorsynth" {
-		art = horrorArt
-	} else if iconKey == "spacesynth" {
-		art = spaceArt
-	} else if iconKey == "rekt" {
-		art = rektArt
-	} else if iconKey == "rektory" {
-		art = RektoryArt
-	} else {
-		art = asciiArt
-	}
+	return renderLogo(barHeights, logoArt(iconKey), CreateGradientForStation(iconKey))
+}
+
+func logoArt(iconKey string) string {
+	return stationLogo(iconKey, asciiArtWidth(), asciiArtHeight())
+}
+
+func renderLogo(barHeights []int, art string, gradient func(int) string) string {
 	artLines := strings.Split(art, "\n")
 
 	height := len(artLines)
-	gradient := CreateGradientForStation(iconKey)
 
 	visualLines := make([]string, height)
 	for y := 0; y < height; y++ {
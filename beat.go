@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+/* ───────────── beat / tempo ───────────── */
+
+const (
+	beatLowHz     = 150.0 // kick drum band, low-passed out of the mix
+	beatHop       = 512   // samples per energy reading
+	beatHistory   = 1.0   // seconds of energy the threshold averages over
+	beatSense     = 1.45  // onset when energy beats the average by this much
+	beatMinGap    = 0.28  // seconds; faster than ~210 BPM is a double hit
+	beatKeep      = 10.0  // seconds of onsets the tempo is estimated from
+	bpmLo, bpmHi  = 80.0, 160.0
+	beatPulseFade = 0.82 // per visualizer tick
+)
+
+// beatDetector flags onsets in the kick band and keeps a tempo estimate
+// from the median gap between them. Time is counted in samples so it
+// follows the audio, not the UI. All state is fixed-size rings so feeding
+// it never allocates.
+type beatDetector struct {
+	sr    float64
+	clock int64
+
+	// kick-band filter: two cascaded one-pole low-passes
+	alpha, y1, y2 float64
+	acc           float64
+	accN          int
+
+	hist       []float64 // recent hop energies
+	hpos, hlen int
+	sum        float64
+
+	onsets     [64]int64
+	opos, olen int
+	gaps       [63]float64
+
+	bpm   float64
+	beats uint64 // onsets so far; the UI watches it change
+}
+
+func newBeatDetector(sr float64) *beatDetector {
+	return &beatDetector{
+		sr:    sr,
+		alpha: 1 - math.Exp(-2*math.Pi*beatLowHz/sr),
+		hist:  make([]float64, max(4, int(beatHistory*sr/beatHop))),
+	}
+}
+
+// sample runs one stereo sample through the kick filter and scores a hop
+// once enough have gone by.
+func (b *beatDetector) sample(x [2]float64) {
+	v := (x[0] + x[1]) * 0.5
+	b.y1 += b.alpha * (v - b.y1)
+	b.y2 += b.alpha * (b.y1 - b.y2)
+	b.acc += b.y2 * b.y2
+	b.accN++
+	if b.accN == beatHop {
+		b.feed(b.acc, beatHop)
+		b.acc, b.accN = 0, 0
+	}
+}
+
+// feed takes the kick-band energy of the n samples since the last call.
+func (b *beatDetector) feed(e float64, n int) {
+	b.clock += int64(n)
+
+	mean := 0.0
+	if b.hlen > 0 {
+		mean = b.sum / float64(b.hlen)
+	}
+	full := b.hlen >= len(b.hist)/2
+	if b.hlen == len(b.hist) {
+		b.sum -= b.hist[b.hpos]
+	} else {
+		b.hlen++
+	}
+	b.hist[b.hpos] = e
+	b.sum += e
+	b.hpos = (b.hpos + 1) % len(b.hist)
+
+	if !full || e <= mean*beatSense || e < 1e-9 {
+		return
+	}
+	if b.olen > 0 && b.clock-b.onsets[(b.opos+len(b.onsets)-1)%len(b.onsets)] < int64(beatMinGap*b.sr) {
+		return
+	}
+	b.onsets[b.opos] = b.clock
+	b.opos = (b.opos + 1) % len(b.onsets)
+	b.olen = min(b.olen+1, len(b.onsets))
+	b.beats++
+	b.bpm = b.estimate()
+}
+
+// estimate folds the median inter-onset gap into bpmLo..bpmHi, where
+// synthwave lives; a missed kick doubles a gap but not the median.
+func (b *beatDetector) estimate() float64 {
+	keep := b.clock - int64(beatKeep*b.sr)
+	gaps := b.gaps[:0]
+	prev := int64(-1)
+	for i := 0; i < b.olen; i++ {
+		t := b.onsets[(b.opos-b.olen+i+len(b.onsets))%len(b.onsets)]
+		if t < keep {
+			continue
+		}
+		if prev >= 0 {
+			gaps = append(gaps, float64(t-prev)/b.sr)
+		}
+		prev = t
+	}
+	if len(gaps) < 3 {
+		return 0
+	}
+	sort.Float64s(gaps)
+	bpm := 60 / gaps[len(gaps)/2]
+	for bpm < bpmLo {
+		bpm *= 2
+	}
+	for bpm >= bpmHi {
+		bpm /= 2
+	}
+	return bpm
+}
+
+// onBeat runs in Update for each frame that carries an onset.
+func (m *model) onBeat(bpm float64) {
+	m.pulse = 1
+	m.bpm = bpm
+	m.lastBeat = time.Now()
+	if m.playingIdx != -1 && len(m.originalTitles[m.playingIdx]) > 43 {
+		m.scrollOffset += m.scrollStep
+	}
+}
+
+// beatLocked is true while onsets keep coming; the marquee then moves on
+// the beat instead of the scroll timer.
+func (m model) beatLocked() bool {
+	return m.bpm > 0 && time.Since(m.lastBeat) < 2*time.Second
+}
+
+func (m model) bpmLabel() string {
+	if !m.beatLocked() {
+		return ""
+	}
+	dot := "○"
+	if m.pulse > 0.5 {
+		dot = "●"
+	}
+	return fmt.Sprintf(" · %s %.0f BPM", dot, m.bpm)
+}
+
+// pulsed brightens a gradient toward white by the current beat pulse.
+func pulsed(grad func(int) string, pulse float64) func(int) string {
+	if pulse < 0.05 {
+		return grad
+	}
+	amt := pulse * 0.45
+	return func(y int) string {
+		r, g, b := parseHexColor(grad(y))
+		mix := func(c int) int { return c + int(float64(255-c)*amt) }
+		return fmt.Sprintf("#%02x%02x%02x", mix(r), mix(g), mix(b))
+	}
+}
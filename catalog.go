@@ -0,0 +1,279 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+/* ───────────── user station catalog ───────────── */
+
+// catalogEntry is one station in stations.json. Entries whose name or
+// metaKey matches a built-in station override its fields, the rest are
+// appended after the built-ins.
+type catalogEntry struct {
+	Name    string       `json:"name"`
+	Streams []string     `json:"streams"`
+	YouTube string       `json:"youtube,omitempty"`
+	Colors  []string     `json:"colors,omitempty"`
+	Art     string       `json:"art,omitempty"`
+	MetaKey string       `json:"metaKey,omitempty"`
+	Meta    *catalogMeta `json:"meta,omitempty"`
+}
+
+type catalogMeta struct {
+	Type    string `json:"type"`              // "nightride", "icecast", "azuracast" or "none"
+	URL     string `json:"url,omitempty"`     // SSE endpoint, status-json.xsl or AzuraCast base URL
+	Station string `json:"station,omitempty"` // AzuraCast shortcode
+}
+
+var (
+	hexColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+	metaKeyRe  = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+)
+
+func catalogPath() string { return filepath.Join(configDir(), "stations.json") }
+
+// loadStationCatalog merges stations.json into the built-in list. Broken
+// entries are skipped and reported in the monitor.
+func loadStationCatalog() {
+	path := catalogPath()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logf("stations: %v", err)
+		}
+		return
+	}
+	var entries []catalogEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		logf("stations: %s: %v", path, err)
+		return
+	}
+
+	seen := map[string]bool{}
+	for i, e := range entries {
+		e.Name = strings.TrimSpace(e.Name)
+		idx := findStation(e)
+		if err := e.validate(idx >= 0); err != nil {
+			logf("stations: entry %d (%q): %v", i+1, e.Name, err)
+			continue
+		}
+		if seen[strings.ToLower(e.Name)] {
+			logf("stations: entry %d (%q): duplicate name", i+1, e.Name)
+			continue
+		}
+		seen[strings.ToLower(e.Name)] = true
+
+		st := station{}
+		if idx >= 0 {
+			st = stations[idx]
+		}
+		if err := e.apply(&st); err != nil {
+			logf("stations: entry %d (%q): %v", i+1, e.Name, err)
+			continue
+		}
+		if idx >= 0 {
+			stations[idx] = st
+			logf("stations: %s overridden from %s", st.name, filepath.Base(path))
+		} else {
+			stations = append(stations, st)
+			logf("stations: added %s", st.name)
+		}
+	}
+}
+
+// saveCatalogEntries appends entries to stations.json, skipping streams
+// that are already in it.
+func saveCatalogEntries(add ...catalogEntry) error {
+	path := catalogPath()
+	var entries []catalogEntry
+	if b, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(b, &entries); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	have := map[string]bool{}
+	for _, e := range entries {
+		for _, s := range e.Streams {
+			have[s] = true
+		}
+	}
+	for _, e := range add {
+		if len(e.Streams) > 0 && have[e.Streams[0]] {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// catalogEntryFor is the inverse of apply, for stations added at runtime.
+func catalogEntryFor(st station) catalogEntry {
+	e := catalogEntry{Name: st.name, Streams: st.streamURLs(), YouTube: st.youtube, MetaKey: st.metaKey}
+	if st.meta.kind != "" {
+		e.Meta = &catalogMeta{Type: st.meta.kind, URL: st.meta.url, Station: st.meta.station}
+	}
+	return e
+}
+
+func findStation(e catalogEntry) int {
+	for i, st := range stations {
+		if strings.EqualFold(st.name, e.Name) || (e.MetaKey != "" && st.id() == e.MetaKey) {
+			return i
+		}
+	}
+	return -1
+}
+
+// validate checks an entry before it touches the station list. Overrides
+// of built-ins may leave out streams.
+func (e catalogEntry) validate(override bool) error {
+	if e.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(e.Streams) == 0 && !override {
+		return errors.New("at least one stream URL is required")
+	}
+	for _, s := range e.Streams {
+		if err := checkHTTPURL(s); err != nil {
+			return fmt.Errorf("stream %q: %w", s, err)
+		}
+	}
+	if e.YouTube != "" {
+		if err := checkHTTPURL(e.YouTube); err != nil {
+			return fmt.Errorf("youtube %q: %w", e.YouTube, err)
+		}
+	}
+	if len(e.Colors) != 0 {
+		if len(e.Colors) != 2 {
+			return fmt.Errorf("colors needs a top and a bottom color, got %d", len(e.Colors))
+		}
+		for _, c := range e.Colors {
+			if !hexColorRe.MatchString(c) {
+				return fmt.Errorf("color %q is not #rrggbb", c)
+			}
+		}
+	}
+	if e.MetaKey != "" && !metaKeyRe.MatchString(e.MetaKey) {
+		return fmt.Errorf("metaKey %q must be lowercase letters, digits, '.', '_' or '-'", e.MetaKey)
+	}
+	if m := e.Meta; m != nil {
+		switch m.Type {
+		case metaNone:
+		case metaNightride:
+			if m.URL != "" {
+				if err := checkHTTPURL(m.URL); err != nil {
+					return fmt.Errorf("meta url %q: %w", m.URL, err)
+				}
+			}
+		case metaIcecast, metaAzuraCast:
+			if err := checkHTTPURL(m.URL); err != nil {
+				return fmt.Errorf("meta url %q: %w", m.URL, err)
+			}
+			if m.Type == metaAzuraCast && m.Station == "" {
+				return errors.New("meta.station (AzuraCast shortcode) is required")
+			}
+		default:
+			return fmt.Errorf("unknown meta type %q", m.Type)
+		}
+	}
+	return nil
+}
+
+func (e catalogEntry) apply(st *station) error {
+	st.name = e.Name
+	if st.url != "" && st.metaKey == "" {
+		st.metaKey = st.id() // an override keeps the built-in's key when its streams move
+	}
+	if len(e.Streams) > 0 {
+		st.url, st.mirrors = e.Streams[0], e.Streams[1:]
+	}
+	if e.YouTube != "" {
+		st.youtube = e.YouTube
+	}
+	if e.MetaKey != "" {
+		st.metaKey = e.MetaKey
+	}
+	if m := e.Meta; m != nil {
+		st.meta = metaSource{kind: m.Type, url: m.URL, station: m.Station}
+	}
+	key := st.iconKey()
+	if e.Art != "" {
+		path := e.Art
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(configDir(), path)
+		}
+		if err := loadStationArt(key, path); err != nil {
+			return err
+		}
+	}
+	if len(e.Colors) == 2 {
+		StationColors[key] = []string{e.Colors[0], e.Colors[1]}
+	}
+	return nil
+}
+
+func checkHTTPURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("must be an http(s) URL")
+	}
+	if u.Host == "" {
+		return errors.New("missing host")
+	}
+	return nil
+}
+
+// loadArtFile reads a text logo and fits it to the visualizer panel.
+func loadArtFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("art: %w", err)
+	}
+	art := strings.ReplaceAll(string(b), "\r\n", "\n")
+	if strings.TrimSpace(art) == "" {
+		return "", fmt.Errorf("art: %s is empty", path)
+	}
+	return fitArt(art, asciiArtWidth(), asciiArtHeight()), nil
+}
+
+// fitArt crops or pads art to exactly w x h runes so custom logos line up
+// with the bars the same way the built-in ones do.
+func fitArt(art string, w, h int) string {
+	lines := strings.Split(strings.Trim(art, "\n"), "\n")
+	out := make([]string, h)
+	top := 0
+	if len(lines) < h {
+		top = (h - len(lines)) / 2
+	}
+	for y := 0; y < h; y++ {
+		var line []rune
+		if i := y - top; i >= 0 && i < len(lines) {
+			line = []rune(strings.TrimRight(lines[i], " \t"))
+		}
+		if len(line) > w {
+			line = line[:w]
+		}
+		out[y] = string(line) + strings.Repeat(" ", w-len(line))
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+/* ───────────── user config ───────────── */
+
+// appConfig is read from config.json in the config dir. Every field is
+// optional; a missing file just means defaults.
+type appConfig struct {
+	Watchlist       []string                       `json:"watchlist"`
+	Blocklist       []string                       `json:"blocklist"`
+	BlocklistReturn bool                           `json:"blocklistReturn"`
+	ListenersURL    string                         `json:"listenersURL"`
+	Backends        []backendConfig                `json:"backends"`
+	DiscoverURL     string                         `json:"discoverURL"`
+	Theme           string                         `json:"theme"`
+	Keys            map[string]map[string][]string `json:"keys"`
+	OSC             oscConfig                      `json:"osc"`
+	Graphics        string                         `json:"graphics"`     // auto, kitty, sixel or off
+	GraphicsTmux    bool                           `json:"graphicsTmux"` // tmux has allow-passthrough on
+}
+
+var cfg appConfig
+
+// configDir returns the directory holding config.json and friends.
+// NIGHTRIDE_CONFIG_DIR overrides the platform default.
+func configDir() string {
+	if d := os.Getenv("NIGHTRIDE_CONFIG_DIR"); d != "" {
+		return d
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return ".nightride"
+	}
+	return filepath.Join(base, "nightride")
+}
+
+func loadConfig() appConfig {
+	var c appConfig
+	path := filepath.Join(configDir(), "config.json")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logf("config: %v", err)
+		}
+		return c
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		logf("config: %s: %v", path, err)
+		return appConfig{}
+	}
+	return c
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nfnt/resize"
+)
+
+/* ───────────── album art ───────────── */
+
+type coverMsg struct {
+	url string
+	s   string
+	err error
+}
+
+const maxCachedCovers = 32
+
+func coverCacheDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(configDir(), "cache", "covers")
+	}
+	return filepath.Join(base, "nightride", "covers")
+}
+
+// fetchCoverCmd downloads (or reads from the disk cache) the artwork at u
+// and renders it as colored ASCII of cols x rows cells.
+func fetchCoverCmd(u string, cols, rows int) tea.Cmd {
+	return func() tea.Msg {
+		img, err := loadCover(u)
+		if err != nil {
+			return coverMsg{url: u, err: err}
+		}
+		if pixelsOK() {
+			return coverMsg{url: u, s: gfxPicture(gfxCoverID, img, cols, rows)}
+		}
+		return coverMsg{url: u, s: renderCoverASCII(img, cols, rows)}
+	}
+}
+
+func loadCover(u string) (image.Image, error) {
+	sum := sha1.Sum([]byte(u))
+	path := filepath.Join(coverCacheDir(), hex.EncodeToString(sum[:]))
+
+	if f, err := os.Open(path); err == nil {
+		defer f.Close()
+		img, _, err := image.Decode(f)
+		if err == nil {
+			return img, nil
+		}
+		logf("cover cache %s: %v", path, err)
+	}
+
+	c := &http.Client{Timeout: 15 * time.Second}
+	resp, err := c.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s", resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			logf("cover cache: %v", err)
+		}
+	}
+	return img, nil
+}
+
+// renderCoverASCII scales img to the panel and reuses the YouTube color
+// converter. Terminal cells are about twice as tall as wide, so a square
+// cover comes out right at cols ≈ 2*rows.
+func renderCoverASCII(img image.Image, cols, rows int) string {
+	scaled := resize.Resize(uint(cols), uint(rows), img, resize.Bilinear)
+	rgba, _ := ensureRGBA(scaled)
+	b := rgba.Bounds()
+	rgb := make([]byte, 0, cols*rows*3)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			o := (y-b.Min.Y)*rgba.Stride + (x-b.Min.X)*4
+			rgb = append(rgb, rgba.Pix[o], rgba.Pix[o+1], rgba.Pix[o+2])
+		}
+	}
+	return strings.TrimSuffix(fastColorASCII(rgb, cols, rows), "\n")
+}
+
+// coverCmd is polled from scrollTick: when the cover view is on and the
+// playing station has artwork we haven't rendered yet, fetch it.
+func (m *model) coverCmd() tea.Cmd {
+	if !m.showCover || m.playingIdx < 0 || m.playingIdx >= len(stations) {
+		return nil
+	}
+	u := stations[m.playingIdx].cover
+	if u == "" || m.coverPending[u] {
+		return nil
+	}
+	if _, ok := m.covers[u]; ok {
+		return nil
+	}
+	m.coverPending[u] = true
+	return fetchCoverCmd(u, asciiArtWidth(), asciiArtHeight())
+}
+
+func (m *model) currentCover() (string, bool) {
+	if m.playingIdx < 0 || m.playingIdx >= len(stations) {
+		return "", false
+	}
+	s, ok := m.covers[stations[m.playingIdx].cover]
+	return s, ok && s != ""
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+/* ───────────── DISCOVER (radio directory) ───────────── */
+
+// Radio Browser (https://www.radio-browser.info) mirrors all serve the
+// same API; point discoverURL at a local stand-in for testing.
+const defaultDiscoverURL = "https://all.api.radio-browser.info"
+
+type dirStation struct {
+	UUID        string `json:"stationuuid"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	URLResolved string `json:"url_resolved"`
+	Homepage    string `json:"homepage"`
+	Tags        string `json:"tags"`
+	Country     string `json:"country"`
+	Codec       string `json:"codec"`
+	Bitrate     int    `json:"bitrate"`
+}
+
+func (d dirStation) Title() string { return strings.TrimSpace(d.Name) }
+func (d dirStation) Description() string {
+	parts := []string{d.Codec}
+	if d.Bitrate > 0 {
+		parts[0] = fmt.Sprintf("%s %dk", d.Codec, d.Bitrate)
+	}
+	if d.Country != "" {
+		parts = append(parts, d.Country)
+	}
+	if d.Tags != "" {
+		parts = append(parts, d.Tags)
+	}
+	return strings.Join(parts, " · ")
+}
+func (d dirStation) FilterValue() string { return d.Name }
+
+func (d dirStation) station() station {
+	u := d.URLResolved
+	if u == "" {
+		u = d.URL
+	}
+	return station{name: d.Title(), url: u, meta: metaSource{kind: metaNone}}
+}
+
+type discoverResultsMsg struct {
+	session int
+	query   string
+	results []dirStation
+	err     error
+}
+
+type discoverModel struct {
+	active  bool
+	input   textinput.Model
+	list    list.Model
+	onList  bool
+	status  string
+	session int
+
+	previewing string // stream URL of the station being previewed
+}
+
+func newDiscoverModel() discoverModel {
+	ti := textinput.New()
+	ti.Placeholder = "synthwave   ·   tag:darkwave country:Germany codec:aac"
+	ti.Prompt = fg(th.Accent).Bold(true).Render("> ")
+
+	l := list.New(nil, stationDelegate("nrfm"), 60, 20)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	return discoverModel{input: ti, list: l}
+}
+
+func (d *discoverModel) open(w, h int) tea.Cmd {
+	d.active = true
+	d.onList = false
+	d.resize(w, h)
+	d.input.Focus()
+	return textinput.Blink
+}
+
+func (d *discoverModel) resize(w, h int) {
+	d.input.Width = max(20, w-6)
+	d.list.SetSize(max(20, w-2), max(4, h-7))
+}
+
+// parseDiscoverQuery turns "darkwave country:Germany codec:mp3" into
+// Radio Browser search parameters. Bare words are tags. Only MP3 plays,
+// so that is the codec unless the query asks for another one.
+func parseDiscoverQuery(q string) url.Values {
+	v := url.Values{}
+	var tags []string
+	for _, f := range strings.Fields(q) {
+		k, val, ok := strings.Cut(f, ":")
+		if !ok {
+			tags = append(tags, f)
+			continue
+		}
+		switch strings.ToLower(k) {
+		case "tag":
+			tags = append(tags, val)
+		case "country":
+			v.Set("country", strings.ReplaceAll(val, "_", " "))
+		case "codec":
+			v.Set("codec", val)
+		case "name":
+			v.Set("name", val)
+		default:
+			tags = append(tags, f)
+		}
+	}
+	if len(tags) > 0 {
+		v.Set("tag", strings.Join(tags, " "))
+	}
+	if v.Get("codec") == "" {
+		v.Set("codec", "MP3")
+	}
+	v.Set("hidebroken", "true")
+	v.Set("order", "clickcount")
+	v.Set("reverse", "true")
+	v.Set("limit", "100")
+	return v
+}
+
+func discoverSearchCmd(session int, q string) tea.Cmd {
+	return func() tea.Msg {
+		base := cfg.DiscoverURL
+		if base == "" {
+			base = defaultDiscoverURL
+		}
+		u := strings.TrimRight(base, "/") + "/json/stations/search?" + parseDiscoverQuery(q).Encode()
+		req, _ := http.NewRequest("GET", u, nil)
+		req.Header.Set("User-Agent", "nightride-cli")
+		c := &http.Client{Timeout: 15 * time.Second}
+		resp, err := c.Do(req)
+		if err != nil {
+			return discoverResultsMsg{session: session, query: q, err: err}
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return discoverResultsMsg{session: session, query: q, err: fmt.Errorf("status %s", resp.Status)}
+		}
+		var res []dirStation
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			return discoverResultsMsg{session: session, query: q, err: err}
+		}
+		return discoverResultsMsg{session: session, query: q, results: res}
+	}
+}
+
+func (d *discoverModel) setResults(msg discoverResultsMsg) {
+	if msg.session != d.session {
+		return
+	}
+	if msg.err != nil {
+		d.status = "search failed: " + msg.err.Error()
+		logf("discover: %v", msg.err)
+		return
+	}
+	items := make([]list.Item, 0, len(msg.results))
+	for _, r := range msg.results {
+		if r.URLResolved == "" && r.URL == "" {
+			continue
+		}
+		items = append(items, r)
+	}
+	d.list.SetItems(items)
+	d.list.Select(0)
+	d.status = fmt.Sprintf("%d stations for %q", len(items), msg.query)
+	if len(items) > 0 {
+		d.onList = true
+		d.input.Blur()
+	}
+}
+
+func (d *discoverModel) selected() (dirStation, bool) {
+	it, ok := d.list.SelectedItem().(dirStation)
+	return it, ok
+}
+
+// updateDiscover handles keys while the Discover screen is up. Preview and
+// add go through the player so they share its playback path.
+func (r *rootModel) updateDiscover(k tea.KeyMsg) tea.Cmd {
+	d := &r.disc
+	if !d.onList {
+		switch keys.action(scrDiscoverSearch, k.String()) {
+		case "back":
+			return r.closeDiscover()
+		case "search":
+			q := strings.TrimSpace(d.input.Value())
+			if q == "" {
+				return nil
+			}
+			d.session++
+			d.status = "searching…"
+			return discoverSearchCmd(d.session, q)
+		case "results":
+			if len(d.list.Items()) > 0 {
+				d.onList = true
+				d.input.Blur()
+			}
+			return nil
+		}
+		var cmd tea.Cmd
+		d.input, cmd = d.input.Update(k)
+		return cmd
+	}
+
+	switch keys.action(scrDiscover, k.String()) {
+	case "back":
+		return r.closeDiscover()
+	case "help":
+		r.help = scrDiscover
+		return nil
+	case "search":
+		d.onList = false
+		d.input.Focus()
+		return textinput.Blink
+	case "preview":
+		ds, ok := d.selected()
+		if !ok {
+			return nil
+		}
+		st := ds.station()
+		if d.previewing == st.url {
+			r.stopPreview()
+			d.status = "preview stopped"
+			return nil
+		}
+		r.player.stopCurrent()
+		r.player.playingIdx = -1
+		d.previewing = st.url
+		d.status = "previewing " + st.name
+		logf("discover: previewing %s (%s)", st.name, st.url)
+		return playStationCmd(st)
+	case "add":
+		ds, ok := d.selected()
+		if !ok {
+			return nil
+		}
+		st := ds.station()
+		added := r.player.addStations([]station{st})
+		if len(added) == 0 {
+			d.status = st.name + " is already in your stations"
+			return nil
+		}
+		if err := saveCatalogEntries(catalogEntryFor(st)); err != nil {
+			logf("discover: save %s: %v", st.name, err)
+			d.status = "added for this session, saving failed: " + err.Error()
+			return nil
+		}
+		d.status = "added " + st.name + " to " + catalogPath()
+		return nil
+	}
+	var cmd tea.Cmd
+	d.list, cmd = d.list.Update(k)
+	return cmd
+}
+
+func (r *rootModel) stopPreview() {
+	if r.disc.previewing == "" {
+		return
+	}
+	r.disc.previewing = ""
+	if r.player.playingIdx == -1 {
+		r.player.stopCurrent()
+	}
+}
+
+func (r *rootModel) closeDiscover() tea.Cmd {
+	r.stopPreview()
+	r.disc.active = false
+	r.disc.input.Blur()
+	return tea.ClearScreen
+}
+
+func (d *discoverModel) View() string {
+	head := fg(th.Header).Render("  Discover · internet radio directory")
+	hint := fmt.Sprintf("[%s] search · [%s] results · [%s] back",
+		keys.label(scrDiscoverSearch, "search"), keys.label(scrDiscoverSearch, "results"), keys.label(scrDiscoverSearch, "back"))
+	if d.onList {
+		hint = fmt.Sprintf("[%s] preview · [%s] add to stations · [%s] search · [%s] help · [%s] back",
+			keys.label(scrDiscover, "preview"), keys.label(scrDiscover, "add"), keys.label(scrDiscover, "search"),
+			keys.label(scrDiscover, "help"), keys.label(scrDiscover, "back"))
+	}
+	status := d.status
+	if d.previewing != "" && !strings.HasPrefix(status, "previewing") {
+		status += " · ▶ preview playing"
+	}
+	dim := fg(th.Dim)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		head,
+		"",
+		" "+d.input.View(),
+		dim.Render("  "+status),
+		d.list.View(),
+		dim.Render("  "+hint),
+	)
+}
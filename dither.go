@@ -0,0 +1,330 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/* ───────────── dithering and picture controls ───────────── */
+
+type ditherKind int
+
+const (
+	ditherNone ditherKind = iota
+	ditherFS
+	ditherBayer
+	ditherBlue
+	ditherCount
+)
+
+var ditherNames = [ditherCount]string{"none", "floyd-steinberg", "bayer", "blue-noise"}
+
+func parseDither(s string) ditherKind {
+	for i, n := range ditherNames {
+		if n == s {
+			return ditherKind(i)
+		}
+	}
+	return ditherNone
+}
+
+// ytLook is the video screen's picture settings. Frames are converted on
+// their own goroutine, so the settings are swapped whole through an
+// atomic pointer rather than edited in place.
+type ytLook struct {
+	dither   ditherKind
+	contrast float64 // 1 leaves the frame alone
+	gamma    float64
+	lut      [256]byte
+	identity bool
+}
+
+var ytLookNow atomic.Pointer[ytLook]
+
+func init() { setYTLook(ditherNone, 1, 1) }
+
+func currentLook() *ytLook { return ytLookNow.Load() }
+
+func setYTLook(d ditherKind, contrast, gamma float64) {
+	l := &ytLook{
+		dither:   d,
+		contrast: math.Round(math.Max(0.2, math.Min(3, contrast))*10) / 10,
+		gamma:    math.Round(math.Max(0.2, math.Min(3, gamma))*10) / 10,
+	}
+	l.identity = l.contrast == 1 && l.gamma == 1
+	for i := range l.lut {
+		v := math.Pow(float64(i)/255, 1/l.gamma)
+		v = (v-0.5)*l.contrast + 0.5
+		l.lut[i] = byte(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+	ytLookNow.Store(l)
+}
+
+// String describes the settings that differ from the defaults.
+func (l *ytLook) String() string {
+	var parts []string
+	if l.dither != ditherNone {
+		parts = append(parts, ditherNames[l.dither])
+	}
+	if l.contrast != 1 {
+		parts = append(parts, "contrast "+strconv.FormatFloat(l.contrast, 'f', 1, 64))
+	}
+	if l.gamma != 1 {
+		parts = append(parts, "gamma "+strconv.FormatFloat(l.gamma, 'f', 1, 64))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// apply runs the contrast/gamma curve over an rgb24 frame in place.
+func (l *ytLook) apply(rgb []byte) {
+	if l.identity {
+		return
+	}
+	for i, v := range rgb {
+		rgb[i] = l.lut[v]
+	}
+}
+
+/* ordered dither thresholds, all in 0..1 */
+
+var bayer8 = func() (m [64]float64) {
+	// recursive Bayer construction: the finest 2x2 level, offsets 0, 2,
+	// 3, 1, ends up in the highest bits
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			v, xx, yy := 0, x, y
+			for bit := 0; bit < 3; bit++ {
+				v = v<<2 | [4]int{0, 2, 3, 1}[(yy&1)<<1|(xx&1)]
+				xx, yy = xx>>1, yy>>1
+			}
+			m[y*8+x] = (float64(v) + 0.5) / 64
+		}
+	}
+	return m
+}()
+
+const blueSize = 64
+
+var (
+	blueOnce  sync.Once
+	blueNoise []float64
+)
+
+// blueNoiseMap builds a 64x64 blue-noise threshold map with Ulichney's
+// void-and-cluster method the first time it's needed (well under a
+// second).
+func blueNoiseMap() []float64 {
+	blueOnce.Do(func() { blueNoise = voidAndCluster(blueSize, 1.5) })
+	return blueNoise
+}
+
+func voidAndCluster(n int, sigma float64) []float64 {
+	size := n * n
+	kernel := make([]float64, size) // toroidal gaussian around (0,0)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			dx, dy := float64(min(x, n-x)), float64(min(y, n-y))
+			kernel[y*n+x] = math.Exp(-(dx*dx + dy*dy) / (2 * sigma * sigma))
+		}
+	}
+	energy := make([]float64, size)
+	on := make([]bool, size)
+	splat := func(p int, sign float64) {
+		px, py := p%n, p/n
+		for y := 0; y < n; y++ {
+			row := ((y - py + n) % n) * n
+			for x := 0; x < n; x++ {
+				energy[y*n+x] += sign * kernel[row+(x-px+n)%n]
+			}
+		}
+	}
+	// tightest cluster: the set pixel with the most energy; largest void:
+	// the empty one with the least
+	extreme := func(want bool) int {
+		best, bestE := -1, 0.0
+		for i, e := range energy {
+			if on[i] != want {
+				continue
+			}
+			if best < 0 || (want && e > bestE) || (!want && e < bestE) {
+				best, bestE = i, e
+			}
+		}
+		return best
+	}
+
+	// initial pattern: random points, relaxed until evenly spread
+	rng := rand.New(rand.NewSource(1))
+	ones := size / 10
+	for _, p := range rng.Perm(size)[:ones] {
+		on[p] = true
+		splat(p, 1)
+	}
+	for {
+		c := extreme(true)
+		on[c] = false
+		splat(c, -1)
+		v := extreme(false)
+		on[v] = true
+		splat(v, 1)
+		if v == c {
+			break
+		}
+	}
+
+	rank := make([]float64, size)
+	initial := append([]bool(nil), on...)
+	initialE := append([]float64(nil), energy...)
+	for r := ones - 1; r >= 0; r-- {
+		c := extreme(true)
+		on[c] = false
+		splat(c, -1)
+		rank[c] = float64(r)
+	}
+	on, energy = initial, initialE
+	for r := ones; r < size; r++ {
+		v := extreme(false)
+		on[v] = true
+		splat(v, 1)
+		rank[v] = float64(r)
+	}
+	for i := range rank {
+		rank[i] = (rank[i] + 0.5) / float64(size)
+	}
+	return rank
+}
+
+// threshold is the ordered-dither offset for a pixel, in -0.5..0.5.
+func threshold(d ditherKind, x, y int) float64 {
+	switch d {
+	case ditherBayer:
+		return bayer8[(y&7)*8+(x&7)] - 0.5
+	case ditherBlue:
+		return blueNoiseMap()[(y%blueSize)*blueSize+x%blueSize] - 0.5
+	}
+	return 0
+}
+
+// ditherLevels quantizes v (w x h values in 0..levels-1) to whole levels.
+// Floyd–Steinberg works on v in place.
+func ditherLevels(v []float64, w, h, levels int, d ditherKind) []byte {
+	out := make([]byte, len(v))
+	top := float64(levels - 1)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			q := math.Round(v[i] + threshold(d, x, y))
+			q = math.Max(0, math.Min(top, q))
+			out[i] = byte(q)
+			if d != ditherFS {
+				continue
+			}
+			e := v[i] - q
+			if x+1 < w {
+				v[i+1] += e * 7 / 16
+			}
+			if y+1 < h {
+				if x > 0 {
+					v[i+w-1] += e * 3 / 16
+				}
+				v[i+w] += e * 5 / 16
+				if x+1 < w {
+					v[i+w+1] += e * 1 / 16
+				}
+			}
+		}
+	}
+	return out
+}
+
+// monoDithered is fastMonoASCII with the ramp index dithered.
+func monoDithered(rgb []byte, cols, rows int, d ditherKind) string {
+	levels := len(ytRamp)
+	v := make([]float64, cols*rows)
+	for i := range v {
+		p := rgb[i*3 : i*3+3]
+		// centred on asciiLUT's buckets, so undithered output matches it
+		v[i] = float64(luma(p[0], p[1], p[2])*(levels-1))/256 - 0.5
+	}
+	q := ditherLevels(v, cols, rows, levels, d)
+	buf := make([]byte, 0, cols*(rows+1))
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			buf = append(buf, ytRamp[q[y*cols+x]])
+		}
+		buf = append(buf, '\n')
+	}
+	return string(buf)
+}
+
+// palette16 picks an ANSI color for every pixel, dithered in RGB. The
+// ordered kinds nudge all three channels by the same threshold, scaled to
+// roughly the palette's step.
+func palette16(rgb []byte, w, h int, d ditherKind) []byte {
+	out := make([]byte, w*h)
+	var errs []float64
+	if d == ditherFS {
+		errs = make([]float64, w*h*3)
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			var c [3]float64
+			t := threshold(d, x, y) * 128
+			for k := 0; k < 3; k++ {
+				c[k] = float64(rgb[i*3+k]) + t
+				if errs != nil {
+					c[k] += errs[i*3+k]
+				}
+			}
+			p := nearest16(c[0], c[1], c[2])
+			out[i] = byte(p)
+			if errs == nil {
+				continue
+			}
+			for k := 0; k < 3; k++ {
+				e := c[k] - ansi16[p][k]
+				if x+1 < w {
+					errs[(i+1)*3+k] += e * 7 / 16
+				}
+				if y+1 < h {
+					if x > 0 {
+						errs[(i+w-1)*3+k] += e * 3 / 16
+					}
+					errs[(i+w)*3+k] += e * 5 / 16
+					if x+1 < w {
+						errs[(i+w+1)*3+k] += e * 1 / 16
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// color16ASCII is fastColorASCII on a 16-color terminal, with the palette
+// dithered.
+func color16ASCII(rgb []byte, cols, rows int, d ditherKind) string {
+	pal := palette16(rgb, cols, rows, d)
+	buf := make([]byte, 0, cols*rows*4)
+	for y := 0; y < rows; y++ {
+		last := penNone
+		for x := 0; x < cols; x++ {
+			i := y*cols + x
+			p := rgb[i*3 : i*3+3]
+			if c := pen(pal[i]); c != last {
+				buf = append(buf, "\x1b["...)
+				buf = appendPen(buf, c, false)
+				buf = append(buf, 'm')
+				last = c
+			}
+			buf = append(buf, asciiLUT[luma(p[0], p[1], p[2])])
+		}
+		buf = append(buf, "\x1b[0m\n"...)
+	}
+	return string(buf)
+}
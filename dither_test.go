@@ -0,0 +1,133 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+var ditherKinds = []ditherKind{ditherNone, ditherFS, ditherBayer, ditherBlue}
+
+// gradient is a w x h rgb24 ramp from black on the left to white on the
+// right, the same on every row.
+func gradient(w, h int) []byte {
+	rgb := make([]byte, 0, w*h*3)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := byte(x * 255 / (w - 1))
+			rgb = append(rgb, v, v, v)
+		}
+	}
+	return rgb
+}
+
+func flat(n int, v float64) []float64 {
+	f := make([]float64, n)
+	for i := range f {
+		f[i] = v
+	}
+	return f
+}
+
+func mean(q []byte) float64 {
+	sum := 0.0
+	for _, v := range q {
+		sum += float64(v)
+	}
+	return sum / float64(len(q))
+}
+
+func TestDitherLevelsExact(t *testing.T) {
+	// half way between two levels: error diffusion alternates them
+	if got := ditherLevels(flat(4, 0.5), 4, 1, 2, ditherFS); string(got) != "\x01\x00\x01\x00" {
+		t.Errorf("floyd-steinberg: got %v, want [1 0 1 0]", got)
+	}
+	// and an 8x8 Bayer tile lights exactly half its cells
+	if got := mean(ditherLevels(flat(64, 0.5), 8, 8, 2, ditherBayer)); got != 0.5 {
+		t.Errorf("bayer: mean %v, want 0.5", got)
+	}
+}
+
+// Dithering trades each pixel's rounding error for noise, so over a patch
+// the average level should match the input; without it, it just rounds.
+func TestDitherLevelsMean(t *testing.T) {
+	const n = blueSize
+	for _, v := range []float64{0.1, 0.5, 2.3, 6.75} {
+		for _, d := range ditherKinds {
+			got := mean(ditherLevels(flat(n*n, v), n, n, 10, d))
+			want, tol := v, 0.02
+			if d == ditherNone {
+				want, tol = math.Round(v), 0
+			}
+			if math.Abs(got-want) > tol {
+				t.Errorf("%s at %v: mean %v, want %v", ditherNames[d], v, got, want)
+			}
+		}
+	}
+}
+
+func TestMonoDithered(t *testing.T) {
+	const w, h = 64, 16
+	rgb := gradient(w, h)
+	if got, want := monoDithered(rgb, w, h, ditherNone), fastMonoASCII(rgb, w, h); got != want {
+		t.Errorf("undithered output differs from fastMonoASCII:\n%s\nwant\n%s", got, want)
+	}
+	// the dithered ramp keeps the gradient's average level, which plain
+	// rounding only gets to within half a step
+	want := 0.0
+	for i := 0; i < w*h; i++ {
+		p := rgb[i*3:]
+		want += float64(luma(p[0], p[1], p[2])*(len(ytRamp)-1))/256 - 0.5
+	}
+	want /= w * h
+	for _, d := range ditherKinds[1:] {
+		out := monoDithered(rgb, w, h, d)
+		if len(out) != (w+1)*h {
+			t.Fatalf("%s: %d bytes, want %d", ditherNames[d], len(out), (w+1)*h)
+		}
+		sum := 0
+		for y := 0; y < h; y++ {
+			row := out[y*(w+1) : (y+1)*(w+1)]
+			if row[0] != ytRamp[0] || row[w] != '\n' {
+				t.Errorf("%s row %d: %q", ditherNames[d], y, row)
+			}
+			for x := 0; x < w; x++ {
+				sum += strings.IndexByte(ytRamp, row[x])
+			}
+		}
+		if got := float64(sum) / (w * h); math.Abs(got-want) > 0.05 {
+			t.Errorf("%s: mean level %.3f, want %.3f", ditherNames[d], got, want)
+		}
+	}
+}
+
+func TestPalette16(t *testing.T) {
+	const n = blueSize
+	// mid gray sits between palette 8 (127) and 7 (229)
+	rgb := make([]byte, n*n*3)
+	for i := range rgb {
+		rgb[i] = 160
+	}
+	for _, d := range ditherKinds {
+		pal := palette16(rgb, n, n, d)
+		sum := 0.0
+		used := map[byte]bool{}
+		for _, p := range pal {
+			sum += ansi16[p][0]
+			used[p] = true
+		}
+		got := sum / float64(len(pal))
+		if d == ditherNone {
+			if len(used) != 1 || !used[8] {
+				t.Errorf("none: used %v, want only 8", used)
+			}
+			continue
+		}
+		if len(used) < 2 {
+			t.Errorf("%s: used only %v", ditherNames[d], used)
+		}
+		if math.Abs(got-160) > 8 {
+			t.Errorf("%s: mean red %.1f, want about 160", ditherNames[d], got)
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"image"
-	"image/color"
 	"io"
 	"os"
 	"path/filepath"
@@ -144,7 +143,7 @@ func ensureRGBA(img image.Image) (*image.RGBA, bool) {
 
 func toASCII(w io.Writer, img *image.RGBA) {
     b := img.Bounds()
-    last := color.RGBA{}
+    last := penNone
     for y := b.Min.Y; y < b.Max.Y; y++ {
         for x := b.Min.X; x < b.Max.X; x++ {
             o := (y-b.Min.Y)*img.Stride + (x-b.Min.X)*4
@@ -156,14 +155,14 @@ func toASCII(w io.Writer, img *image.RGBA) {
             if idx < 0 { idx = 0 }
             if idx >= len(ramp) { idx = len(ramp) - 1 }
             ch := ramp[idx]
-            if r != last.R || g != last.G || bl != last.B {
-                fmt.Fprintf(w, "\x1b[38;2;%d;%d;%dm", r, g, bl)
-                last = color.RGBA{r, g, bl, 255}
+            if p := penFor(r, g, bl); p != last {
+                _, _ = w.Write(append(appendPen([]byte("\x1b["), p, false), 'm'))
+                last = p
             }
             _, _ = w.Write([]byte{byte(ch)})
         }
         _, _ = w.Write([]byte("\x1b[0m\r\n"))
-        last = color.RGBA{}
+        last = penNone
     }
 }
 
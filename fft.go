@@ -0,0 +1,129 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+/* ───────────── spectrum analyzer ───────────── */
+
+const (
+	fftSize = 2048 // ~46ms at 44.1kHz, ~21Hz per bin
+	specLo  = 40.0 // Hz, left edge of the first column
+	specHi  = 16000.0
+)
+
+// spectrum turns the most recent fftSize mono samples into one magnitude
+// per art column: Hann window, FFT, then log-spaced bands so bass lands on
+// the left and cymbals on the right.
+type spectrum struct {
+	ring   []float64
+	pos    int
+	window []float64
+	buf    []complex128
+	bands  [][2]int // FFT bin range per column
+	weight []float64
+	sr     float64
+	cols   int
+}
+
+func newSpectrum(sampleRate float64, cols int) *spectrum {
+	s := &spectrum{
+		ring:   make([]float64, fftSize),
+		window: make([]float64, fftSize),
+		buf:    make([]complex128, fftSize),
+		sr:     sampleRate,
+		cols:   cols,
+	}
+	for i := range s.window {
+		s.window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(fftSize-1))
+	}
+	s.layout()
+	return s
+}
+
+// resize re-bands the analyzer for a new column count, keeping the sample
+// history.
+func (s *spectrum) resize(cols int) {
+	s.cols = cols
+	s.layout()
+}
+
+// layout spreads cols bands evenly in log frequency. Bands narrower than a
+// bin at the bottom end share the nearest one.
+func (s *spectrum) layout() {
+	hi := math.Min(specHi, s.sr/2)
+	binHz := s.sr / fftSize
+	s.bands = make([][2]int, s.cols)
+	s.weight = make([]float64, s.cols)
+	ratio := hi / specLo
+	for c := 0; c < s.cols; c++ {
+		f0 := specLo * math.Pow(ratio, float64(c)/float64(s.cols))
+		f1 := specLo * math.Pow(ratio, float64(c+1)/float64(s.cols))
+		b0 := int(math.Floor(f0 / binHz))
+		b1 := int(math.Ceil(f1 / binHz))
+		if b1 <= b0 {
+			b1 = b0 + 1
+		}
+		b1 = min(b1, fftSize/2)
+		b0 = min(b0, b1-1)
+		s.bands[c] = [2]int{b0, b1}
+		// Music falls off about 3dB per octave; tilt it back so the top
+		// columns move as much as the bottom ones.
+		s.weight[c] = math.Sqrt(math.Sqrt(f0*f1) / specLo)
+	}
+}
+
+func (s *spectrum) push(x [2]float64) {
+	s.ring[s.pos] = (x[0] + x[1]) * 0.5
+	s.pos++
+	if s.pos == fftSize {
+		s.pos = 0
+	}
+}
+
+// analyze fills out (len cols) with band magnitudes of the current window.
+func (s *spectrum) analyze(out []float64) {
+	for i := 0; i < fftSize; i++ {
+		s.buf[i] = complex(s.ring[(s.pos+i)%fftSize]*s.window[i], 0)
+	}
+	fft(s.buf)
+	for c, b := range s.bands {
+		var peak float64
+		for k := b[0]; k < b[1]; k++ {
+			if m := cmplx.Abs(s.buf[k]); m > peak {
+				peak = m
+			}
+		}
+		out[c] = peak / fftSize * s.weight[c]
+	}
+}
+
+// fft is an in-place iterative radix-2 Cooley–Tukey; len(a) must be a power
+// of two.
+func fft(a []complex128) {
+	n := len(a)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		w := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			wk := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				u := a[start+k]
+				v := a[start+k+size/2] * wk
+				a[start+k] = u + v
+				a[start+k+size/2] = u - v
+				wk *= w
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+/* ───────────── full-screen visualizer ───────────── */
+
+// vizCols is how many spectrum columns the UI wants; the analyzer checks
+// it every tick and re-bands its spectrum when it changes.
+var vizCols atomic.Int32
+
+func init() { vizCols.Store(int32(asciiArtWidth())) }
+
+// vizSize is the panel the visualizer draws into: the art box normally,
+// the whole terminal less the title line in full-screen.
+func (m model) vizSize() (int, int) {
+	if m.fullscreen && m.termW > 0 && m.termH > 0 {
+		return max(8, m.termW), max(4, m.termH-1)
+	}
+	return asciiArtWidth(), asciiArtHeight()
+}
+
+func (m *model) resizeViz() {
+	w, _ := m.vizSize()
+	if len(m.barHeights) != w {
+		m.barHeights = make([]int, w)
+		m.waterfall = nil
+	}
+	vizCols.Store(int32(w))
+}
+
+func (m *model) setFullscreen(on bool) {
+	m.fullscreen = on
+	m.resizeViz()
+}
+
+var scaledArtCache = map[string]string{}
+
+// scaledArt resamples art to w x h cells, nearest neighbour, so block and
+// line characters stay crisp.
+func scaledArt(iconKey, art string, w, h int) string {
+	key := fmt.Sprintf("%s/%dx%d", iconKey, w, h)
+	if s, ok := scaledArtCache[key]; ok {
+		return s
+	}
+	src := strings.Split(art, "\n")
+	srcW := 0
+	rows := make([][]rune, len(src))
+	for i, l := range src {
+		rows[i] = []rune(l)
+		srcW = max(srcW, len(rows[i]))
+	}
+	out := make([]string, h)
+	for y := 0; y < h; y++ {
+		row := rows[y*len(rows)/h]
+		line := make([]rune, w)
+		for x := range line {
+			sx := x * srcW / w
+			line[x] = ' '
+			if sx < len(row) {
+				line[x] = row[sx]
+			}
+		}
+		out[y] = string(line)
+	}
+	if len(scaledArtCache) > 32 {
+		scaledArtCache = map[string]string{}
+	}
+	s := strings.Join(out, "\n")
+	scaledArtCache[key] = s
+	return s
+}
+
+func (m model) fullscreenView() string {
+	iconKey := "nrfm"
+	head := "  ▐▐ PAUSED"
+	if m.playingIdx >= 0 && m.playingIdx < len(stations) {
+		st := stations[m.playingIdx]
+		iconKey = st.iconKey()
+		head = "  " + st.name + m.bpmLabel()
+		if t := m.originalTitles[m.playingIdx]; t != "" {
+			head += " · " + t
+		}
+	}
+	if r := []rune(head); len(r) > max(10, m.termW-20) {
+		head = string(r[:max(10, m.termW-20)-1]) + "…"
+	}
+	return fg(th.Header).Render(head) + fg(th.Dim).Render("  any key to return") + "\n" + m.renderViz(iconKey)
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nfnt/resize"
+)
+
+/* ───────────── terminal graphics ───────────── */
+
+// On terminals that speak the kitty graphics protocol or Sixel, video and
+// cover art are drawn as real pixels. Everywhere else, and whenever the
+// protocol can't be trusted, the ASCII renderers are used instead.
+
+type gfxProto int
+
+const (
+	gfxNone gfxProto = iota
+	gfxKitty
+	gfxSixel
+)
+
+var gfxNames = [...]string{"off", "kitty", "sixel"}
+
+var (
+	gfx     gfxProto
+	gfxTmux bool // wrap every sequence for tmux passthrough
+)
+
+// kitty image ids, so a new frame replaces the last one in place
+const (
+	gfxVideoID = 1
+	gfxCoverID = 2
+)
+
+// gfxVideoPixels caps the frame kitty is sent; it scales the image to the
+// cell box itself, so more pixels only cost bandwidth.
+const gfxVideoPixels = 480 * 270
+
+// detectGraphics picks the protocol from config ("auto", "kitty", "sixel"
+// or "off") or, on auto, the environment. tmux swallows graphics unless
+// passthrough is on, so inside it pixels need graphicsTmux as well.
+func detectGraphics(mode string, tmux bool) {
+	switch strings.ToLower(mode) {
+	case "off", "none":
+		gfx = gfxNone
+	case "kitty":
+		gfx = gfxKitty
+	case "sixel":
+		gfx = gfxSixel
+	case "", "auto":
+		gfx = sniffGraphics()
+	default:
+		logf("config: unknown graphics %q, using auto", mode)
+		gfx = sniffGraphics()
+	}
+	if gfx != gfxNone && os.Getenv("TMUX") != "" {
+		if !tmux {
+			logf("graphics: inside tmux, falling back to ASCII (set graphicsTmux and tmux's allow-passthrough to use %s)", gfxNames[gfx])
+			gfx = gfxNone
+			return
+		}
+		gfxTmux = true
+	}
+	if gfx != gfxNone {
+		logf("graphics: %s", gfxNames[gfx])
+	}
+}
+
+// sniffGraphics goes by the variables terminals set about themselves.
+// LC_TERMINAL and KITTY_WINDOW_ID survive into tmux; TERM_PROGRAM doesn't.
+func sniffGraphics() gfxProto {
+	term, prog := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty",
+		prog == "ghostty", term == "xterm-ghostty", prog == "WezTerm":
+		return gfxKitty
+	case prog == "iTerm.app", os.Getenv("LC_TERMINAL") == "iTerm2",
+		strings.HasPrefix(term, "foot"), strings.Contains(term, "mlterm"),
+		strings.Contains(term, "contour"):
+		return gfxSixel
+	}
+	return gfxNone
+}
+
+// termCellSize is one cell in pixels, as the tty reports it. Terminals
+// that don't say get a common 8x16.
+func termCellSize() (int, int) {
+	if w, h := ttyCellSize(); w > 0 && h > 0 {
+		return w, h
+	}
+	return 8, 16
+}
+
+// gfxCellPixels is how many frame pixels a cell gets in pixel mode for a
+// box of cols x rows cells.
+func gfxCellPixels(cols, rows int) (int, int) {
+	if gfx == gfxSixel {
+		return termCellSize() // sixel draws 1:1, so fill the cells exactly
+	}
+	k := int(math.Sqrt(float64(gfxVideoPixels) / float64(2*max(1, cols*rows))))
+	k = max(1, k)
+	return k, 2 * k
+}
+
+func gfxWrap(seq string) string {
+	if !gfxTmux {
+		return seq
+	}
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+// gfxImage encodes an rgb24 frame of w x h pixels to fill cols x rows.
+func gfxImage(id int, rgb []byte, w, h, cols, rows int) string {
+	if gfx == gfxSixel {
+		return gfxWrap(sixelEncode(rgb, w, h))
+	}
+	return kittyEncode(id, rgb, w, h, cols, rows)
+}
+
+// gfxBlock lays an image sequence into cols x rows of blank text so it
+// can sit in a View. The sequence rides on the last line, after that
+// line's spaces, and walks the cursor back to the block's top-left corner:
+// lines are painted top to bottom, so nothing in the block can overwrite
+// it afterwards, and the spaces keep the renderer from erasing the row.
+func gfxBlock(seq string, cols, rows int) string {
+	blank := strings.Repeat(" ", cols)
+	var b strings.Builder
+	for i := 0; i < rows-1; i++ {
+		b.WriteString(blank)
+		b.WriteByte('\n')
+	}
+	b.WriteString(blank)
+	b.WriteString("\x1b7")
+	if rows > 1 {
+		fmt.Fprintf(&b, "\x1b[%dA", rows-1)
+	}
+	fmt.Fprintf(&b, "\x1b[%dD", cols)
+	b.WriteString(seq)
+	b.WriteString("\x1b8")
+	return b.String()
+}
+
+// gfxClear removes a kitty image; Views put it in front of whatever
+// replaces the image, so it goes out when that line is repainted. Sixel
+// pixels are ordinary cells and get painted over.
+func gfxClear(id int) string {
+	if gfx != gfxKitty {
+		return ""
+	}
+	return gfxWrap(fmt.Sprintf("\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", id))
+}
+
+// gfxPicture scales img to fill cols x rows and returns it as a block.
+func gfxPicture(id int, img image.Image, cols, rows int) string {
+	cw, ch := gfxCellPixels(cols, rows)
+	w, h := cols*cw, rows*ch
+	rgba, _ := ensureRGBA(resize.Resize(uint(w), uint(h), img, resize.Bilinear))
+	rgb := make([]byte, 0, w*h*3)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			o := y*rgba.Stride + x*4
+			rgb = append(rgb, rgba.Pix[o], rgba.Pix[o+1], rgba.Pix[o+2])
+		}
+	}
+	return gfxBlock(gfxImage(id, rgb, w, h, cols, rows), cols, rows)
+}
+
+// kittyEncode sends the frame zlib-compressed in 4K chunks, placed over
+// cols x rows cells without moving the cursor. q=2 keeps the terminal
+// from answering, which would arrive as keypresses.
+func kittyEncode(id int, rgb []byte, w, h, cols, rows int) string {
+	var z bytes.Buffer
+	zw, _ := zlib.NewWriterLevel(&z, zlib.BestSpeed)
+	zw.Write(rgb[:w*h*3])
+	zw.Close()
+	data := base64.StdEncoding.EncodeToString(z.Bytes())
+
+	var b strings.Builder
+	for first := true; first || data != ""; first = false {
+		chunk := data[:min(4096, len(data))]
+		data = data[len(chunk):]
+		more := 0
+		if data != "" {
+			more = 1
+		}
+		var seq string
+		if first {
+			seq = fmt.Sprintf("\x1b_Ga=T,q=2,f=24,o=z,s=%d,v=%d,i=%d,p=1,c=%d,r=%d,C=1,m=%d;%s\x1b\\",
+				w, h, id, cols, rows, more, chunk)
+		} else {
+			seq = fmt.Sprintf("\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+		b.WriteString(gfxWrap(seq))
+	}
+	return b.String()
+}
+
+// sixelEncode maps the frame onto a 6x6x6 color cube and writes it band
+// by band, one run-length-encoded pass per color present in the band.
+func sixelEncode(rgb []byte, w, h int) string {
+	idx := make([]byte, w*h)
+	for i := range idx {
+		p := rgb[i*3 : i*3+3]
+		idx[i] = byte(cube6(p[0])*36 + cube6(p[1])*6 + cube6(p[2]))
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1bP0;1;0q")
+	fmt.Fprintf(&b, "\"1;1;%d;%d", w, h)
+	for c := 0; c < 216; c++ {
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", c, c/36*20, c/6%6*20, c%6*20)
+	}
+
+	var (
+		masks [216][]byte
+		seen  [216]bool
+		used  []int
+	)
+	for top := 0; top < h; top += 6 {
+		for _, c := range used {
+			seen[c] = false
+		}
+		used = used[:0]
+		for y := top; y < min(top+6, h); y++ {
+			bit := byte(1) << (y - top)
+			for x := 0; x < w; x++ {
+				c := idx[y*w+x]
+				if masks[c] == nil {
+					masks[c] = make([]byte, w)
+				}
+				if !seen[c] {
+					seen[c] = true
+					used = append(used, int(c))
+					clear(masks[c])
+				}
+				masks[c][x] |= bit
+			}
+		}
+		for i, c := range used {
+			if i > 0 {
+				b.WriteByte('$')
+			}
+			b.WriteByte('#')
+			b.WriteString(strconv.Itoa(c))
+			sixelRow(&b, masks[c])
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+func cube6(v byte) int { return (int(v)*5 + 127) / 255 }
+
+// sixelRow writes one color's masks for a band, with !n repeats.
+func sixelRow(b *strings.Builder, row []byte) {
+	for x := 0; x < len(row); {
+		n := 1
+		for x+n < len(row) && row[x+n] == row[x] {
+			n++
+		}
+		ch := byte(63 + row[x])
+		if n > 3 {
+			b.WriteByte('!')
+			b.WriteString(strconv.Itoa(n))
+			b.WriteByte(ch)
+		} else {
+			for i := 0; i < n; i++ {
+				b.WriteByte(ch)
+			}
+		}
+		x += n
+	}
+}
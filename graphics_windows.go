@@ -0,0 +1,4 @@
+package main
+
+// The Windows console API has no pixel size for the window.
+func ttyCellSize() (int, int) { return 0, 0 }
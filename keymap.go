@@ -0,0 +1,302 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+/* ───────────── keymap ───────────── */
+
+// Every screen looks its keys up here instead of matching strings, so
+// config.json can rebind them and the help overlay can't drift:
+//
+//	"keys": { "player": { "play": ["enter", "space"] } }
+//
+// Keys use Bubble Tea's names ("ctrl+t", "left", "a", "A").
+
+type keyBinding struct {
+	action string
+	keys   []string
+	help   string
+}
+
+type keyScreen struct {
+	name     string
+	title    string
+	inherit  bool   // global bindings also apply here
+	helpWith string // another screen whose bindings share the help box
+	bindings []keyBinding
+	lookup   map[string]string
+}
+
+const (
+	scrGlobal         = "global"
+	scrPlayer         = "player"
+	scrYouTube        = "youtube"
+	scrDiscover       = "discover"
+	scrDiscoverSearch = "discover-search"
+	scrIRC            = "irc"
+	scrIRCServers     = "irc-servers"
+)
+
+func defaultKeymap() []*keyScreen {
+	return []*keyScreen{
+		{name: scrGlobal, title: "EVERYWHERE", bindings: []keyBinding{
+			{"switch-tab", []string{"tab"}, "Player / IRC"},
+			{"next-theme", []string{"ctrl+t"}, "Next theme"},
+		}},
+		{name: scrPlayer, title: "PLAYER", inherit: true, bindings: []keyBinding{
+			{"up", []string{"up", "k"}, "Previous station"},
+			{"down", []string{"down", "j"}, "Next station"},
+			{"page-up", []string{"pgup"}, "Previous page"},
+			{"page-down", []string{"pgdown"}, "Next page"},
+			{"first", []string{"home"}, "First station"},
+			{"last", []string{"end"}, "Last station"},
+			{"play", []string{"enter"}, "Play/Pause"},
+			{"layout", []string{"l"}, "Toggle layout"},
+			{"help", []string{"h"}, "Show/hide help"},
+			{"doom", []string{"d", "D"}, "Toggle Doom"},
+			{"monitor", []string{"m"}, "Monitor logs"},
+			{"youtube", []string{"y", "Y"}, "YouTube ASCII"},
+			{"discover", []string{"s", "S"}, "Discover stations"},
+			{"volume-up", []string{"+", "="}, "Volume up"},
+			{"volume-down", []string{"-"}, "Volume down"},
+			{"open-url", []string{"u"}, "Open stream URL"},
+			{"jump-watch", []string{"w"}, "Jump to watch hit"},
+			{"viz-mode", []string{"v"}, "Visualizer mode"},
+			{"fullscreen", []string{"f"}, "Full-screen visualizer"},
+			{"cover", []string{"c"}, "Logo / cover art"},
+			{"easter-egg", []string{"z"}, "Easter egg"},
+			{"quit", []string{"q", "ctrl+c"}, "Quit"},
+		}},
+		{name: scrYouTube, title: "YOUTUBE", bindings: []keyBinding{
+			{"prev-station", []string{"left"}, "Previous station"},
+			{"next-station", []string{"right"}, "Next station"},
+			{"color", []string{"c", "C"}, "Render mode: mono, color, half-block, braille, pixels"},
+			{"scene", []string{"v", "V"}, "Next scene (no-video stations)"},
+			{"dither", []string{"d", "D"}, "Dithering: none, Floyd–Steinberg, Bayer, blue noise"},
+			{"contrast-down", []string{"["}, "Less contrast"},
+			{"contrast-up", []string{"]"}, "More contrast"},
+			{"gamma-down", []string{"{"}, "Darker midtones"},
+			{"gamma-up", []string{"}"}, "Brighter midtones"},
+			{"stats", []string{"i", "I"}, "Show/hide output stats (bytes per frame, fps)"},
+			{"resize", []string{"ctrl+-", "ctrl+_", "ctrl+=", "ctrl+plus", "ctrl+shift+="}, "Refit to window"},
+			{"help", []string{"h"}, "Show/hide help"},
+			{"back", []string{"q", "Q"}, "Back"},
+		}},
+		{name: scrDiscoverSearch, title: "DISCOVER SEARCH", bindings: []keyBinding{
+			{"search", []string{"enter"}, "Search"},
+			{"results", []string{"down", "tab"}, "Go to results"},
+			{"back", []string{"esc"}, "Back"},
+		}},
+		{name: scrDiscover, title: "DISCOVER", bindings: []keyBinding{
+			{"preview", []string{"enter", "p"}, "Preview / stop"},
+			{"add", []string{"a"}, "Add to stations"},
+			{"search", []string{"/", "tab"}, "Edit search"},
+			{"help", []string{"h"}, "Show/hide help"},
+			{"back", []string{"esc", "q"}, "Back"},
+		}},
+		{name: scrIRC, title: "IRC", inherit: true, bindings: []keyBinding{
+			{"focus-servers", []string{"left"}, "Servers pane"},
+			{"focus-chat", []string{"right"}, "Chat pane"},
+			{"quit", []string{"ctrl+c", "esc"}, "Quit"},
+		}},
+		{name: scrIRCServers, title: "IRC SERVERS", helpWith: scrIRC, bindings: []keyBinding{
+			{"open", []string{"enter"}, "Open server"},
+			{"add", []string{"a"}, "Add server"},
+			{"delete", []string{"d"}, "Remove server"},
+			{"help", []string{"h"}, "Show/hide help"},
+		}},
+	}
+}
+
+var keys = newKeymap(nil)
+
+type keymap struct {
+	screens map[string]*keyScreen
+	order   []string
+}
+
+// newKeymap applies overrides (screen -> action -> keys) on top of the
+// defaults and logs anything it can't use.
+func newKeymap(overrides map[string]map[string][]string) keymap {
+	km := keymap{screens: map[string]*keyScreen{}}
+	for _, s := range defaultKeymap() {
+		km.screens[s.name] = s
+		km.order = append(km.order, s.name)
+	}
+	for scr, acts := range overrides {
+		s := km.screens[scr]
+		if s == nil {
+			logf("keys: unknown screen %q (have %s)", scr, km.screenNames())
+			continue
+		}
+		for act, ks := range acts {
+			found := false
+			for i := range s.bindings {
+				if s.bindings[i].action == act {
+					s.bindings[i].keys = normalizeKeys(ks)
+					found = true
+				}
+			}
+			if !found {
+				logf("keys: %s has no action %q", scr, act)
+			}
+		}
+	}
+	for _, name := range km.order {
+		km.build(km.screens[name])
+	}
+	return km
+}
+
+// Bubble Tea reports the space bar as " "; accept the readable name too.
+func normalizeKeys(ks []string) []string {
+	out := make([]string, len(ks))
+	for i, k := range ks {
+		if strings.EqualFold(k, "space") {
+			k = " "
+		}
+		out[i] = k
+	}
+	return out
+}
+
+// build fills the lookup table. A key bound twice on one screen (or shared
+// with an inherited global binding) keeps its first action; the others are
+// reported in the monitor.
+func (km keymap) build(s *keyScreen) {
+	s.lookup = map[string]string{}
+	taken := map[string]string{}
+	if s.inherit {
+		for _, b := range km.screens[scrGlobal].bindings {
+			for _, k := range b.keys {
+				taken[k] = scrGlobal + "." + b.action
+			}
+		}
+	}
+	for _, b := range s.bindings {
+		for _, k := range b.keys {
+			if prev, ok := taken[k]; ok {
+				logf("keys: %s: %q is bound to both %s and %s, keeping %s", s.name, k, prev, b.action, prev)
+				continue
+			}
+			taken[k] = b.action
+			s.lookup[k] = b.action
+		}
+	}
+}
+
+// action returns what k does on screen, or "" if nothing.
+func (km keymap) action(screen, k string) string {
+	if s := km.screens[screen]; s != nil {
+		return s.lookup[k]
+	}
+	return ""
+}
+
+// label is the first key bound to an action, for hints like "[H] Help".
+func (km keymap) label(screen, action string) string {
+	if s := km.screens[screen]; s != nil {
+		for _, b := range s.bindings {
+			if b.action == action && len(b.keys) > 0 {
+				return keyName(b.keys[0])
+			}
+		}
+	}
+	return "?"
+}
+
+var keyNames = map[string]string{
+	"up": "↑", "down": "↓", "left": "←", "right": "→",
+	"enter": "Enter", "esc": "Esc", "tab": "Tab", " ": "Space",
+	"pgup": "PgUp", "pgdown": "PgDn", "home": "Home", "end": "End",
+}
+
+func keyName(k string) string {
+	if n, ok := keyNames[k]; ok {
+		return n
+	}
+	if strings.HasPrefix(k, "ctrl+") {
+		return "Ctrl+" + strings.ToUpper(k[5:])
+	}
+	if utf8.RuneCountInString(k) == 1 {
+		return strings.ToUpper(k)
+	}
+	return k
+}
+
+// bindingKeys lists a binding's keys, folding case-only duplicates
+// ("d", "D" -> "D").
+func bindingKeys(b keyBinding) string {
+	seen := map[string]bool{}
+	var out []string
+	for _, k := range b.keys {
+		n := keyName(k)
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	if len(out) > 2 {
+		out = append(out[:2], "…")
+	}
+	return strings.Join(out, ",")
+}
+
+// helpText draws the help box for a screen from its bindings (and its
+// helpWith screen's), plus the global ones when they apply there.
+func (km keymap) helpText(screen string) string {
+	s := km.screens[screen]
+	if s == nil {
+		return ""
+	}
+	type row struct{ k, h string }
+	var rows []row
+	add := func(bs []keyBinding) {
+		for _, b := range bs {
+			if len(b.keys) > 0 {
+				rows = append(rows, row{bindingKeys(b), b.help})
+			}
+		}
+	}
+	add(s.bindings)
+	inherit := s.inherit
+	if w := km.screens[s.helpWith]; w != nil {
+		add(w.bindings)
+		inherit = inherit || w.inherit
+	}
+	if inherit {
+		add(km.screens[scrGlobal].bindings)
+	}
+
+	kw, hw := 0, 0
+	for _, r := range rows {
+		kw = max(kw, lipgloss.Width(r.k))
+		hw = max(hw, lipgloss.Width(r.h))
+	}
+	inner := kw + hw + 4
+	title := " " + s.title + " HELP "
+	inner = max(inner, lipgloss.Width(title)+4)
+	left := (inner - lipgloss.Width(title)) / 2
+
+	var b strings.Builder
+	b.WriteString("\n┌" + strings.Repeat("─", left) + title + strings.Repeat("─", inner-left-lipgloss.Width(title)) + "┐\n")
+	for _, r := range rows {
+		line := fmt.Sprintf(" %s%s  %s", r.k, strings.Repeat(" ", kw-lipgloss.Width(r.k)), r.h)
+		b.WriteString("│" + line + strings.Repeat(" ", inner-lipgloss.Width(line)) + "│\n")
+	}
+	b.WriteString("└" + strings.Repeat("─", inner) + "┘\n")
+	return b.String()
+}
+
+// screenNames lists the screens a config may rebind, for error messages.
+func (km keymap) screenNames() string {
+	names := append([]string(nil), km.order...)
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/* ───────────── listener counts ───────────── */
+
+// Nightride's streams are served by Icecast, whose status page lists every
+// mount with its current listener count.
+const defaultListenersURL = "https://stream.nightride.fm/status-json.xsl"
+
+const (
+	listenersEvery = 30 * time.Second
+	trendSamples   = int(time.Hour / listenersEvery)
+	sparkWidth     = 10
+)
+
+type listenersMsg map[string]int
+
+var (
+	listenersOnce sync.Once
+	listenersChan = make(chan listenersMsg, 4)
+
+	listenerMu     sync.Mutex
+	listenerCounts = map[string]int{}
+)
+
+// of finds st's count. The status page names mounts by the server's own
+// host, which needn't be the one in the station's URL, so streams are
+// matched by their last path element.
+func (m listenersMsg) of(st station) (int, bool) {
+	for _, u := range st.streamURLs() {
+		if n, ok := m[stationKey(u)]; ok {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+func startListenersCmd() tea.Cmd {
+	return func() tea.Msg { listenersOnce.Do(func() { go listenersLoop() }); return nil }
+}
+
+func waitListenersCmd() tea.Cmd { return func() tea.Msg { return <-listenersChan } }
+
+// currentListeners returns the last polled count for a stream key
+// ("darksynth.mp3"), or 0 if unknown.
+func currentListeners(key string) int {
+	listenerMu.Lock()
+	defer listenerMu.Unlock()
+	return listenerCounts[key]
+}
+
+func listenersLoop() {
+	u := cfg.ListenersURL
+	if u == "" {
+		u = defaultListenersURL
+	}
+	c := &http.Client{Timeout: 10 * time.Second}
+	for {
+		counts, err := fetchIcecastListeners(c, u)
+		if err != nil {
+			logf("listeners: %v", err)
+		} else {
+			listenerMu.Lock()
+			for k, n := range counts {
+				listenerCounts[k] = n
+			}
+			listenerMu.Unlock()
+			select {
+			case listenersChan <- counts:
+			default:
+			}
+		}
+		time.Sleep(listenersEvery)
+	}
+}
+
+type icecastSource struct {
+	ListenURL  string `json:"listenurl"`
+	Listeners  int    `json:"listeners"`
+	ServerName string `json:"server_name"`
+	Artist     string `json:"artist"`
+	Title      string `json:"title"`
+}
+
+func (s icecastSource) nowPlaying() string { return joinArtistTitle(s.Artist, s.Title) }
+
+type icecastStatus struct {
+	Icestats struct {
+		// Icecast sends an object when there is a single mount and an
+		// array otherwise.
+		Source json.RawMessage `json:"source"`
+	} `json:"icestats"`
+}
+
+func fetchIcecastListeners(c *http.Client, u string) (map[string]int, error) {
+	sources, err := fetchIcecastSources(c, u)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]int, len(sources))
+	for _, s := range sources {
+		if s.ListenURL == "" {
+			continue
+		}
+		out[stationKey(s.ListenURL)] = s.Listeners
+	}
+	return out, nil
+}
+
+func fetchIcecastSources(c *http.Client, u string) ([]icecastSource, error) {
+	var st icecastStatus
+	if err := getJSON(c, u, &st); err != nil {
+		return nil, err
+	}
+	var sources []icecastSource
+	raw := st.Icestats.Source
+	if len(raw) > 0 && raw[0] == '{' {
+		var one icecastSource
+		if err := json.Unmarshal(raw, &one); err != nil {
+			return nil, err
+		}
+		sources = append(sources, one)
+	} else if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &sources); err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+func appendTrend(trend []int, n int) []int {
+	if len(trend) >= trendSamples {
+		trend = append(trend[:0:0], trend[len(trend)-trendSamples+1:]...)
+	}
+	return append(trend, n)
+}
+
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline squeezes up to an hour of samples into sparkWidth cells,
+// scaled between the window's min and max.
+func sparkline(trend []int) string {
+	if len(trend) < 2 {
+		return ""
+	}
+	per := (trendSamples + sparkWidth - 1) / sparkWidth
+	var cols []float64
+	for i := 0; i < len(trend); i += per {
+		end := min(i+per, len(trend))
+		sum := 0
+		for _, v := range trend[i:end] {
+			sum += v
+		}
+		cols = append(cols, float64(sum)/float64(end-i))
+	}
+	lo, hi := cols[0], cols[0]
+	for _, v := range cols {
+		lo = min64(lo, v)
+		hi = max64(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range cols {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(sparkRunes)-1))
+		}
+		b.WriteRune(sparkRunes[i])
+	}
+	return b.String()
+}
+
+func min64(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
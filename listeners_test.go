@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestListenerCountsByStream(t *testing.T) {
+	srv := serveJSON(t, "/status-json.xsl", `{"icestats":{"source":[
+		{"listenurl":"http://internal:8000/darksynth.mp3","listeners":40},
+		{"listenurl":"http://internal:8000/live","listeners":7},
+		{"listenurl":"http://internal:8000/backup","listeners":2}]}}`)
+	counts, err := fetchIcecastListeners(http.DefaultClient, srv.URL+"/status-json.xsl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		st   station
+		want int
+		ok   bool
+	}{
+		{station{url: "https://stream.nightride.fm/darksynth.mp3"}, 40, true},
+		{station{url: "https://radio.example.com/live"}, 7, true},
+		{station{url: "https://radio.example.com/down", mirrors: []string{"https://radio.example.com/backup"}}, 2, true},
+		{station{url: "https://radio.example.com/other"}, 0, false},
+	}
+	for _, tt := range tests {
+		if n, ok := listenersMsg(counts).of(tt.st); n != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %d %v, want %d %v", tt.st.url, n, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"bufio"
 	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
 	"image"
@@ -23,9 +22,11 @@ import (
 
 	"github.com/babycommando/rich-go/client"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/faiface/beep"
+	"github.com/faiface/beep/effects"
 	"github.com/faiface/beep/mp3"
 	"github.com/faiface/beep/speaker"
 )
@@ -96,10 +97,12 @@ func redirectStderrToMonitor() func() {
 /* ─────────────  Bubble Tea model (player)  ───────────── */
 
 type (
-	metaAllMsg = map[string]struct {
+	stationMeta struct {
 		title     string
 		listeners int
+		cover     string
 	}
+	metaAllMsg      = map[string]stationMeta
 	errMsg          error
 	streamHandleMsg struct {
 		streamer beep.StreamSeekCloser
@@ -114,7 +117,11 @@ type model struct {
 	streamer           beep.StreamSeekCloser
 	respBody           io.Closer
 	barHeights         []int
-	ampChan            chan []float64
+	frame              vizFrame // reused between ticks
+	frameSeq           uint64
+	beatsSeen          uint64
+	blurBuf            []int
+	ticks              int // scroll ticks so far
 	easterEgg          bool
 	visPeak            float64
 	isHorizontalLayout bool
@@ -124,6 +131,24 @@ type model struct {
 	originalTitles     map[int]string
 	scrollStep         int
 	showMonitor        bool
+	watch              keywordList
+	alert              *watchAlert
+	alertBlink         bool
+	showCover          bool
+	covers             map[string]string // artwork URL -> rendered ASCII
+	coverPending       map[string]bool
+	vizMode            int
+	scope              [][2]float64
+	vu, vuPeak         [2]float64
+	vuPeakAt           [2]time.Time
+	waterfall          [][]float64
+	pulse, bpm         float64
+	lastBeat           time.Time
+	fullscreen         bool
+	termW, termH       int
+	urlInput           textinput.Model
+	prompting          bool
+	promptStatus       string
 }
 
 type fadeIn struct {
@@ -163,6 +188,40 @@ func (f *fadeIn) Stream(samples [][2]float64) (int, bool) {
 	return n, ok
 }
 
+// Volume is applied after the visualizer tap, so the bars don't shrink
+// when it is turned down.
+var (
+	volumePct = 100
+	volumeCtl *effects.Volume
+)
+
+func withVolume(s beep.Streamer) beep.Streamer {
+	v := &effects.Volume{Streamer: s, Base: 2}
+	speaker.Lock()
+	volumeCtl = v
+	applyVolume()
+	speaker.Unlock()
+	return v
+}
+
+// setVolume clamps to 0–150% and applies it to the playing stream.
+func setVolume(pct int) {
+	speaker.Lock()
+	volumePct = min(150, max(0, pct))
+	applyVolume()
+	speaker.Unlock()
+}
+
+func applyVolume() {
+	if volumeCtl == nil {
+		return
+	}
+	volumeCtl.Silent = volumePct == 0
+	if volumePct > 0 {
+		volumeCtl.Volume = math.Log2(float64(volumePct) / 100)
+	}
+}
+
 func (f *fadeIn) Err() error {
 	if e, ok := f.s.(interface{ Err() error }); ok {
 		return e.Err()
@@ -170,11 +229,10 @@ func (f *fadeIn) Err() error {
 	return nil
 }
 
-func (m *model) updateSelectorColors(iconKey string) {
-	colors, exists := StationColors[iconKey]
-	if !exists {
-		colors = []string{"#ff386f", "#7d3cff"}
-	}
+// stationDelegate is the list delegate used for station lists, with the
+// selection tinted in the station's colors.
+func stationDelegate(iconKey string) list.DefaultDelegate {
+	colors := stationColors(iconKey)
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Copy().
 		Foreground(lipgloss.Color(colors[0])).
@@ -182,7 +240,11 @@ func (m *model) updateSelectorColors(iconKey string) {
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Copy().
 		Foreground(lipgloss.Color(colors[1])).
 		BorderLeftForeground(lipgloss.Color(colors[1]))
-	m.l.SetDelegate(delegate)
+	return delegate
+}
+
+func (m *model) updateSelectorColors(iconKey string) {
+	m.l.SetDelegate(stationDelegate(iconKey))
 }
 
 func min(a, b int) int { if a < b { return a }; return b }
@@ -195,17 +257,22 @@ func newModel() model {
 	}
 	delegate := list.NewDefaultDelegate()
 	l := list.New(items, delegate, 46, len(items)*2-3)
-	l.Title = "↑/↓ Navigate · [↵] Play/Pause · [H] Help"
+	l.Title = fmt.Sprintf("%s/%s Navigate · [%s] Play/Pause · [%s] Help",
+		keys.label(scrPlayer, "up"), keys.label(scrPlayer, "down"), keys.label(scrPlayer, "play"), keys.label(scrPlayer, "help"))
 	l.Styles.Title = l.Styles.Title.Copy().
 		UnsetBackground().
 		MarginTop(2).
 		Margin(0, 0).
 		Padding(0, 0).
-		Foreground(lipgloss.Color("#CCCCCC")).
+		Foreground(th.Muted).
 		Faint(true)
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
 	l.SetShowHelp(false)
+	// every key goes through the keymap; the list's own bindings would
+	// survive remapping and quit without stopping the stream
+	l.DisableQuitKeybindings()
+	l.KeyMap = list.KeyMap{}
 
 	originalTitles := make(map[int]string)
 	for i := range stations {
@@ -217,22 +284,20 @@ func newModel() model {
 		playingIdx:         0,
 		startTime:          time.Now(),
 		barHeights:         make([]int, asciiArtWidth()),
-		ampChan:            make(chan []float64, 1),
 		visPeak:            0.25,
 		isHorizontalLayout: true,
 		showHelp:           false,
 		originalTitles:     originalTitles,
 		scrollStep:         1,
+		watch:              newKeywordList(cfg.Watchlist),
+		covers:             map[string]string{},
+		coverPending:       map[string]bool{},
+		urlInput:           newURLInput(),
 	}
 
 	if len(stations) > 0 {
 		st := stations[0]
-		iconKey := strings.ToLower(stationKey(st.url))
-		iconKey = strings.TrimSuffix(iconKey, ".mp3")
-		if iconKey == "nightride" {
-			iconKey = "nrfm"
-		}
-		m.updateSelectorColors(iconKey)
+		m.updateSelectorColors(st.iconKey())
 	}
 
 	return m
@@ -240,9 +305,12 @@ func newModel() model {
 
 func (m model) Init() tea.Cmd {
 	return tea.Batch(
-		startStreamCmd(0, m.ampChan),
+		m.resumeCmd(),
 		startSSECmd(),
 		waitMetaCmd(),
+		startListenersCmd(),
+		waitListenersCmd(),
+		loadBackendsCmd(),
 		visualizerTick(),
 		scrollTick(),
 	)
@@ -253,23 +321,68 @@ const globalGain = 0.55
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "m":
+		if m.prompting {
+			return m, m.updatePrompt(msg)
+		}
+		if m.fullscreen {
+			m.setFullscreen(false)
+			return m, tea.ClearScreen
+		}
+		switch act := keys.action(scrPlayer, msg.String()); act {
+		case "open-url":
+			m.prompting = true
+			m.promptStatus = ""
+			m.urlInput.SetValue("")
+			m.urlInput.Focus()
+			return m, textinput.Blink
+		case "monitor":
 			m.showMonitor = !m.showMonitor
 			return m, nil
-		case "h":
+		case "help":
 			m.showHelp = !m.showHelp
 			return m, nil
-		case "l":
+		case "layout":
 			m.isHorizontalLayout = !m.isHorizontalLayout
 			return m, nil
-		case "z":
+		case "easter-egg":
 			m.easterEgg = !m.easterEgg
 			return m, nil
-		case "q", "ctrl+c":
+		case "fullscreen":
+			m.setFullscreen(true)
+			return m, tea.ClearScreen
+		case "viz-mode":
+			m.vizMode = (m.vizMode + 1) % vizModes
+			m.waterfall = nil
+			logf("visualizer: %s", vizNames[m.vizMode])
+			return m, nil
+		case "cover":
+			m.showCover = !m.showCover
+			return m, m.coverCmd()
+		case "jump-watch":
+			if m.alert == nil {
+				return m, nil
+			}
+			idx := m.alert.idx
+			m.alert = nil
+			if idx == m.playingIdx || idx >= len(stations) {
+				return m, nil
+			}
+			m.stopCurrent()
+			m.playingIdx = idx
+			m.startTime = time.Now()
+			m.scrollOffset = 0
+			m.l.Select(idx)
+			return m, startStreamCmd(idx)
+		case "volume-up":
+			setVolume(volumePct + 5)
+			return m, nil
+		case "volume-down":
+			setVolume(volumePct - 5)
+			return m, nil
+		case "quit":
 			m.stopCurrent()
 			return m, tea.Quit
-		case "enter":
+		case "play":
 			idx := m.l.Index()
 			if m.playingIdx == idx {
 				m.stopCurrent()
@@ -279,23 +392,34 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.stopCurrent()
 			m.playingIdx = idx
 			m.startTime = time.Now()
-			return m, startStreamCmd(idx, m.ampChan)
-		case "up", "down", "k", "j":
-			var cmd tea.Cmd
-			m.l, cmd = m.l.Update(msg)
+			return m, startStreamCmd(idx)
+		case "up", "down", "page-up", "page-down", "first", "last":
+			switch act {
+			case "up":
+				m.l.CursorUp()
+			case "down":
+				m.l.CursorDown()
+			case "page-up":
+				m.l.PrevPage()
+			case "page-down":
+				m.l.NextPage()
+			case "first":
+				m.l.Select(0)
+			case "last":
+				m.l.Select(len(m.l.Items()) - 1)
+			}
 			m.listScrollOffset = 0
 			idx := m.l.Index()
 			if idx < len(stations) {
 				st := stations[idx]
-				iconKey := strings.ToLower(stationKey(st.url))
-				iconKey = strings.TrimSuffix(iconKey, ".mp3")
-				if iconKey == "nightride" {
-					iconKey = "nrfm"
-				}
-				m.updateSelectorColors(iconKey)
+				m.updateSelectorColors(st.iconKey())
 			}
-			return m, cmd
+			return m, nil
 		}
+	case tea.WindowSizeMsg:
+		m.termW, m.termH = msg.Width, msg.Height
+		m.resizeViz()
+		return m, nil
 	case streamHandleMsg:
 		m.streamer, m.respBody = msg.streamer, msg.body
 		return m, nil
@@ -304,17 +428,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			st := itm.(station)
 			key := st.id() + ".mp3"
 			if meta, ok := msg[key]; ok {
-				st.title, st.listeners = meta.title, meta.listeners
+				if meta.title != m.originalTitles[i] {
+					m.checkWatchlist(i, meta.title)
+				}
+				st.title, st.listeners, st.cover = meta.title, meta.listeners, meta.cover
 				m.l.SetItem(i, st)
-				stations[i].title, stations[i].listeners = st.title, st.listeners
+				stations[i].title, stations[i].listeners, stations[i].cover = st.title, st.listeners, st.cover
 				m.originalTitles[i] = meta.title
 
 				if i == m.playingIdx {
-					iconKey := strings.ToLower(stationKey(st.url))
-					iconKey = strings.TrimSuffix(iconKey, ".mp3")
-					if iconKey == "nightride" {
-						iconKey = "nrfm"
-					}
+					iconKey := st.iconKey()
 
 					parts := strings.SplitN(st.title, " - ", 2)
 					artist := ""
@@ -333,7 +456,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						LargeText:  track,
 						Timestamps: &client.Timestamps{Start: &m.startTime},
 						Buttons: []*client.Button{
-							{Label: "Listen to " + st.name, Url: "https://nightride.fm/?station=" + st.id()},
+							{Label: "Listen to " + st.name, Url: st.pageURL()},
 							{Label: "Join the Discord", Url: "https://discord.gg/synthwave"},
 						},
 					})
@@ -344,14 +467,47 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		return m, waitMetaCmd()
+	case listenersMsg:
+		for i, itm := range m.l.Items() {
+			st := itm.(station)
+			n, ok := msg.of(st)
+			if !ok {
+				continue
+			}
+			st.listeners = n
+			st.trend = appendTrend(st.trend, n)
+			m.l.SetItem(i, st)
+			stations[i].listeners, stations[i].trend = st.listeners, st.trend
+		}
+		return m, waitListenersCmd()
+	case urlStationMsg:
+		return m, m.playURLStation(msg)
+	case stationsAddedMsg:
+		added := m.addStations(msg.stations)
+		logf("backend %s: %d stations added", msg.from, len(added))
+		startMetaProviders(added)
+		return m, nil
+	case coverMsg:
+		delete(m.coverPending, msg.url)
+		if msg.err != nil {
+			logf("cover: %v", msg.err)
+			m.covers[msg.url] = "" // don't retry a broken URL every tick
+			return m, nil
+		}
+		if len(m.covers) >= maxCachedCovers {
+			m.covers = map[string]string{}
+		}
+		m.covers[msg.url] = msg.s
+		return m, nil
 	case errMsg:
 		logf("audio error: %v", msg)
 		return m, nil
 	case string:
 		if msg == "visualizerTick" {
-			select {
-			case amps := <-m.ampChan:
-				height := asciiArtHeight()
+			if latestViz(&m.frame, &m.frameSeq) && len(m.frame.bands) == len(m.barHeights) {
+				f := m.frame
+				amps := f.bands
+				_, height := m.vizSize()
 				frameMax := 0.0
 				for _, a := range amps {
 					if a > frameMax {
@@ -382,20 +538,37 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.barHeights[i] = int(a*shaped + (1-a)*current)
 				}
 				prev := m.barHeights
-				blur := make([]int, len(prev))
+				blur := resized(m.blurBuf, len(prev))
 				for i := range prev {
 					l := prev[max(i-1, 0)]
 					m2 := prev[i]
 					r := prev[min(i+1, len(prev)-1)]
 					blur[i] = (l + m2*2 + r) / 4
 				}
-				m.barHeights = blur
-			default:
+				m.barHeights, m.blurBuf = blur, prev
+				m.feedViz(f, height)
+				if f.beats != m.beatsSeen {
+					m.beatsSeen = f.beats
+					m.onBeat(f.bpm)
+				}
 			}
+			m.pulse *= beatPulseFade
 			return m, visualizerTick()
 		}
 		if msg == "scrollTick" {
-			if m.playingIdx != -1 {
+			m.ticks++
+			if m.alert != nil {
+				m.alertBlink = !m.alertBlink
+				if time.Since(m.alert.at) > watchAlertTTL {
+					m.alert = nil
+				}
+			}
+			if m.playingIdx >= 0 && m.playingIdx < len(stations) {
+				setOSCTitle(stations[m.playingIdx].name, m.originalTitles[m.playingIdx])
+			} else {
+				setOSCTitle("", "")
+			}
+			if m.playingIdx != -1 && !m.beatLocked() {
 				originalTitle := m.originalTitles[m.playingIdx]
 				if len(originalTitle) > 43 {
 					m.scrollOffset += m.scrollStep
@@ -422,7 +595,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.l.SetItem(hoveredIdx, hoveredStation)
 				}
 			}
-			return m, scrollTick()
+			return m, tea.Batch(scrollTick(), m.coverCmd())
 		}
 	}
 
@@ -439,14 +612,21 @@ func (m model) View() string {
 			b.WriteString(s)
 			b.WriteByte('\n')
 		}
-		title := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD75F")).Render("Monitor [M to exit]")
-		box := lipgloss.NewStyle().Border(lipgloss.NormalBorder()).Padding(1, 2).Width(asciiArtWidth() + 56)
+		title := fg(th.Header).Render("Monitor [M to exit]")
+		if st := sseStatusLines(); len(st) > 0 {
+			title += "\n" + lipgloss.NewStyle().Faint(true).Render(strings.Join(st, "\n"))
+		}
+		box := lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(th.Border).Padding(1, 2).Width(asciiArtWidth() + 56)
 		return box.Render(title + "\n" + b.String())
 	}
 
+	if m.fullscreen {
+		return m.fullscreenView()
+	}
+
 	if m.easterEgg {
 		return lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ff386f")).
+			Foreground(th.Accent).
 			Render(EasterEgg)
 	}
 
@@ -455,11 +635,7 @@ func (m model) View() string {
 
 	if m.playingIdx != -1 {
 		item := m.l.Items()[m.playingIdx].(station)
-		currentIconKey = strings.ToLower(stationKey(item.url))
-		currentIconKey = strings.TrimSuffix(currentIconKey, ".mp3")
-		if currentIconKey == "nightride" {
-			currentIconKey = "nrfm"
-		}
+		currentIconKey = item.iconKey()
 
 		originalTitle := m.originalTitles[m.playingIdx]
 		displayTitle := originalTitle
@@ -477,33 +653,52 @@ func (m model) View() string {
 			}
 		}
 
-		header = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD75F")).
-			Render("  " + item.name + "\n  " + "▶ " + displayTitle)
+		name := item.name + m.bpmLabel()
+		if volumePct != 100 {
+			name += fmt.Sprintf(" · vol %d%%", volumePct)
+		}
+		header = fg(th.Header).
+			Render("  " + name + "\n  " + "▶ " + displayTitle)
+	}
+
+	if m.alert != nil {
+		c := th.Accent
+		if m.alertBlink {
+			c = th.Header
+		}
+		title := []rune(m.alert.title)
+		if len(title) > 28 {
+			title = append(title[:27], '…')
+		}
+		line := fmt.Sprintf("  ★ %s on %s · [%s] jump", string(title), m.alert.station, keys.label(scrPlayer, "jump-watch"))
+		header += "\n" + fg(c).Bold(true).Render(line)
+	}
+
+	if m.prompting {
+		header += "\n  " + m.urlInput.View()
+	} else if m.promptStatus != "" {
+		header += "\n" + fg(th.Dim).Render("  "+m.promptStatus)
 	}
 
 	var visual string
 	if m.showHelp {
-		controlsText := `
-┌──────────── HELP ────────────┐
-│ ↑/↓, j/k  Navigate stations  │
-│ Enter     Play/Pause         │
-│ L         Toggle layout      │
-│ H         Show/hide help     │
-│ D         Toggle Doom        │
-│ M         Monitor logs       │
-│ Y         YouTube ASCII      │
-│ Z         Easter egg         │
-│ Q,Ctrl+C  Quit               │
-└──────────────────────────────┘
-`
+		controlsText := keys.helpText(scrPlayer)
 		asciiWidth := asciiArtWidth()
 		visual = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ff386f")).
+			Foreground(th.Accent).
 			Width(asciiWidth).
 			Render(controlsText)
+	} else if s, ok := m.currentCover(); ok && m.showCover && !th.Mono {
+		visual = s
+		if gfx == gfxSixel && m.ticks%2 == 1 {
+			// a no-op that changes the line, so the renderer repaints the
+			// image after anything that was drawn over it
+			visual += "\x1b[0m"
+		}
 	} else {
-		visual = RenderVisualizedASCII(m.barHeights, currentIconKey)
+		visual = gfxClear(gfxCoverID) + m.renderViz(currentIconKey)
 	}
+	visual = gfxClear(gfxVideoID) + visual
 
 	if m.isHorizontalLayout {
 		rightPanel := lipgloss.JoinVertical(lipgloss.Left, header, m.l.View())
@@ -552,10 +747,27 @@ func dialAndDecode(u string, tries int) (beep.StreamSeekCloser, beep.Format, io.
 	return nil, beep.Format{}, nil, fmt.Errorf("failed to decode")
 }
 
-func startStreamCmd(idx int, ampChan chan []float64) tea.Cmd {
+func startStreamCmd(idx int) tea.Cmd {
+	return playStationCmd(stations[idx])
+}
+
+// playStationCmd streams st whether or not it is in the catalog, which is
+// how Discover previews play.
+func playStationCmd(st station) tea.Cmd {
 	return func() tea.Msg {
-		st := stations[idx]
-		decoded, format, body, err := dialAndDecode(st.url, 5)
+		var (
+			decoded beep.StreamSeekCloser
+			format  beep.Format
+			body    io.ReadCloser
+			err     = fmt.Errorf("%s: no playable stream", st.name)
+		)
+		for _, u := range resolveStreamURLs(st.streamURLs()) {
+			decoded, format, body, err = dialAndDecode(u, 5)
+			if err == nil {
+				break
+			}
+			logf("stream %s: %v", u, err)
+		}
 		if err != nil {
 			return errMsg(err)
 		}
@@ -564,7 +776,7 @@ func startStreamCmd(idx int, ampChan chan []float64) tea.Cmd {
 			speaker.Init(mixerSampleRate, mixerSampleRate.N(time.Second/10))
 		})
 
-		vs := &visualizerStreamer{Streamer: beep.Streamer(decoded), ampChan: ampChan, width: asciiArtWidth()}
+		vs := newVisualizerStreamer(decoded, format.SampleRate)
 
 		playStream := beep.Streamer(vs)
 		if format.SampleRate != mixerSampleRate {
@@ -573,13 +785,9 @@ func startStreamCmd(idx int, ampChan chan []float64) tea.Cmd {
 		fade := newFadeIn(playStream, mixerSampleRate, 650*time.Millisecond)
 
 		speaker.Clear()
-		speaker.Play(fade)
+		speaker.Play(withVolume(fade))
 
-		iconKey := strings.ToLower(stationKey(st.url))
-		iconKey = strings.TrimSuffix(iconKey, ".mp3")
-		if iconKey == "nightride" {
-			iconKey = "nrfm"
-		}
+		iconKey := st.iconKey()
 
 		parts := strings.SplitN(st.title, " - ", 2)
 		artist := ""
@@ -599,7 +807,7 @@ func startStreamCmd(idx int, ampChan chan []float64) tea.Cmd {
 			LargeText:  track,
 			Timestamps: &client.Timestamps{Start: &now},
 			Buttons: []*client.Button{
-				{Label: "Listen to " + st.name, Url: "https://nightride.fm/?station=" + st.id()},
+				{Label: "Listen to " + st.name, Url: st.pageURL()},
 				{Label: "Join the Discord", Url: "https://discord.gg/synthwave"},
 			},
 		})
@@ -614,6 +822,15 @@ type nowPlaying struct {
 	Station string `json:"station"`
 	Title   string `json:"title"`
 	Artist  string `json:"artist"`
+	Art     string `json:"art"`
+	Artwork string `json:"artwork"`
+}
+
+func (np nowPlaying) coverURL() string {
+	if np.Artwork != "" {
+		return np.Artwork
+	}
+	return np.Art
 }
 
 var (
@@ -622,124 +839,14 @@ var (
 )
 
 func startSSECmd() tea.Cmd {
-	return func() tea.Msg { sseOnce.Do(func() { go sseLoop() }); return nil }
+	return func() tea.Msg { sseOnce.Do(func() { startMetaProviders(stations) }); return nil }
 }
 
 func waitMetaCmd() tea.Cmd { return func() tea.Msg { return <-sseChan } }
 
-func sseLoop() {
-	clientHTTP := &http.Client{Timeout: 0}
-	backoff := time.Second
-
-	for {
-		req, _ := http.NewRequest("GET", "https://nightride.fm/meta", nil)
-		req = req.WithContext(context.Background())
-		req.Header.Set("Accept", "text/event-stream")
-		req.Header.Set("Cache-Control", "no-cache")
-
-		resp, err := clientHTTP.Do(req)
-		if err != nil {
-			logf("meta connect: %v", err)
-			time.Sleep(backoff)
-			if backoff < 30*time.Second {
-				backoff *= 2
-			}
-			continue
-		}
-		if resp.StatusCode != http.StatusOK {
-			logf("meta status: %s", resp.Status)
-			resp.Body.Close()
-			time.Sleep(backoff)
-			if backoff < 30*time.Second {
-				backoff *= 2
-			}
-			continue
-		}
-
-		backoff = time.Second
-		reader := bufio.NewReader(resp.Body)
-
-		for {
-			line, err := reader.ReadString('\n')
-			if err != nil {
-				logf("meta read: %v", err)
-				resp.Body.Close()
-				break
-			}
-			line = strings.TrimSpace(line)
-			if !strings.HasPrefix(line, "data: ") {
-				continue
-			}
-			payload := strings.TrimPrefix(line, "data: ")
-			if payload == "" || payload == "keepalive" {
-				continue
-			}
-
-			var entries []nowPlaying
-			if err := json.Unmarshal([]byte(payload), &entries); err != nil {
-				continue
-			}
-
-			update := metaAllMsg{}
-			for _, np := range entries {
-				update[np.Station+".mp3"] = struct {
-					title     string
-					listeners int
-				}{
-					title:     fmt.Sprintf("%s - %s", np.Artist, np.Title),
-					listeners: 0,
-				}
-			}
-
-			select {
-			case sseChan <- update:
-			default:
-			}
-		}
-	}
-}
-
 func visualizerTick() tea.Cmd { return tea.Tick(33*time.Millisecond, func(time.Time) tea.Msg { return "visualizerTick" }) }
 func scrollTick() tea.Cmd     { return tea.Tick(300*time.Millisecond, func(time.Time) tea.Msg { return "scrollTick" }) }
 
-type visualizerStreamer struct {
-	beep.Streamer
-	ampChan chan []float64
-	width   int
-}
-
-func (vs *visualizerStreamer) Stream(samples [][2]float64) (int, bool) {
-	n, ok := vs.Streamer.Stream(samples)
-
-	cols := vs.width
-	amps := make([]float64, cols)
-	bucket := n / cols
-	if bucket == 0 {
-		bucket = 1
-	}
-
-	for c := 0; c < cols; c++ {
-		start := c * bucket
-		end := start + bucket
-		if end > n {
-			end = n
-		}
-		var sumSq float64
-		for i := start; i < end; i++ {
-			s := (samples[i][0] + samples[i][1]) * 0.5
-			sumSq += s * s
-		}
-		rms := math.Sqrt(sumSq / float64(end-start))
-		amps[c] = rms
-	}
-
-	select {
-	case vs.ampChan <- amps:
-	default:
-	}
-	return n, ok
-}
-
 /* ───────────── IN-APP YouTube ASCII SCREEN ───────────── */
 
 const ytRamp = " .:-=+*#%@"
@@ -772,7 +879,11 @@ type ytModel struct {
 	frame     string
 	hasVideo  bool
 	loading   bool
-	colorMode bool
+	mode      ytMode
+
+	scene     *scene // generated visuals when the station has no video
+	sceneKind int    // -1 picks per station
+	sceneBuf  []byte
 
 	session int
 
@@ -786,14 +897,18 @@ func (y *ytModel) View() string {
 	if !y.active {
 		return ""
 	}
+	if y.scene != nil {
+		return y.sceneView()
+	}
 	if !y.hasVideo {
-		msg := "This station has no video stream. ←/→ switch · [Q] back"
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#aaa")).
+		msg := fmt.Sprintf("This station has no video stream. %s/%s switch · [%s] back",
+			keys.label(scrYouTube, "prev-station"), keys.label(scrYouTube, "next-station"), keys.label(scrYouTube, "back"))
+		return gfxClear(gfxVideoID) + fg(th.Muted).
 			Render(centerLine(msg, max(10, y.cols)))
 	}
 	if y.loading {
 		msg := "Loading video…"
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#aaa")).
+		return gfxClear(gfxVideoID) + fg(th.Muted).
 			Render(centerLine(msg, max(10, y.cols)))
 	}
 
@@ -807,14 +922,17 @@ func (y *ytModel) View() string {
 	if meta != "" {
 		head += " · " + meta
 	}
-	headStyled := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD75F")).Render(head)
-
-	mode := "color"
-	if y.colorMode {
-		mode = "no color"
+	if look := currentLook().String(); look != "" {
+		head += " · " + look
 	}
-	controls := fmt.Sprintf("[←/→] station · [Ctrl -/+] resize · [C] %s · [Q] quit", mode)
-	bar := lipgloss.NewStyle().Foreground(lipgloss.Color("#888")).Render(padOrTrim(controls, max(10, y.cols)))
+	headStyled := fg(th.Header).Render(head)
+
+	next := ytModeNames[y.mode.next()]
+	l := func(a string) string { return keys.label(scrYouTube, a) }
+	controls := fmt.Sprintf("[%s/%s] station · [Ctrl -/+] resize · [%s] %s · [%s] dither · [%s %s] contrast · [%s %s] gamma · [%s] stats · [%s] quit",
+		l("prev-station"), l("next-station"), l("color"), next, l("dither"),
+		l("contrast-down"), l("contrast-up"), l("gamma-down"), l("gamma-up"), l("stats"), l("back"))
+	bar := fg(th.Dim).Render(padOrTrim(controls, max(10, y.cols)))
 
 	return headStyled + "\n" + y.frame + "\n" + bar
 }
@@ -833,10 +951,12 @@ func (y *ytModel) start(idx, cols, rows int) tea.Cmd {
 	y.hasVideo = false
 	y.session++
 
+	y.scene = nil
+
 	st := stations[idx]
 	if st.youtube == "" {
-		y.hasVideo = false
-		return nil
+		y.startScene(st.iconKey())
+		return sceneTick(y.session)
 	}
 
 	u, err := resolveYouTubeLiveHLS(st.youtube)
@@ -847,7 +967,8 @@ func (y *ytModel) start(idx, cols, rows int) tea.Cmd {
 		return func() tea.Msg { return ytErrMsg{err: err, session: s} }
 	}
 
-	cmd, out, err := startFFMPEG(u, y.cols, y.rows)
+	pw, ph := y.pixelDims()
+	cmd, out, err := startFFMPEG(u, pw, ph)
 	if err != nil {
 		y.hasVideo = false
 		y.loading = false
@@ -871,21 +992,16 @@ func (y *ytModel) start(idx, cols, rows int) tea.Cmd {
 		}
 	}(cur)
 
-	go readFrames(out, y.cols*y.rows*3, y.frames)
+	go readFrames(out, pw*ph*3, y.frames)
 
-	go func(col, row, sess int, ch <-chan []byte) {
+	go func(w, h, sess int, ch <-chan []byte) {
 		for f := range ch {
-			var s string
-			if y.colorMode {
-				s = fastColorASCII(f, col, row)
-			} else {
-				s = fastMonoASCII(f, col, row)
-			}
+			s := y.toASCII(f, w, h)
 			if app != nil {
 				app.Send(ytFrameMsg{s: s, session: sess})
 			}
 		}
-	}(y.cols, y.rows, cur, y.frames)
+	}(pw, ph, cur, y.frames)
 
 	return nil
 }
@@ -925,11 +1041,12 @@ func (y *ytModel) nextIndex(delta int) int {
 	return i % n
 }
 
-/* extremely fast color ASCII conversion */
+/* extremely fast color ASCII conversion; colors quantized to the terminal's depth */
 func fastColorASCII(rgb []byte, cols, rows int) string {
-	buf := make([]byte, 0, cols*rows*24)
+	buf := make([]byte, 0, cols*rows*12)
 	i := 0
 	for y := 0; y < rows; y++ {
+		last := penNone
 		for x := 0; x < cols; x++ {
 			r := rgb[i]
 			g := rgb[i+1]
@@ -937,13 +1054,13 @@ func fastColorASCII(rgb []byte, cols, rows int) string {
 			i += 3
 			y8 := (54*int(r) + 183*int(g) + 19*int(b)) >> 8
 			ch := asciiLUT[y8]
-			buf = append(buf, '\x1b', '[', '3', '8', ';', '2', ';')
-			buf = strconv.AppendInt(buf, int64(r), 10)
-			buf = append(buf, ';')
-			buf = strconv.AppendInt(buf, int64(g), 10)
-			buf = append(buf, ';')
-			buf = strconv.AppendInt(buf, int64(b), 10)
-			buf = append(buf, 'm', ch)
+			if p := penFor(r, g, b); p != last {
+				buf = append(buf, '\x1b', '[')
+				buf = appendPen(buf, p, false)
+				buf = append(buf, 'm')
+				last = p
+			}
+			buf = append(buf, ch)
 		}
 		buf = append(buf, '\x1b', '[', '0', 'm', '\n')
 	}
@@ -997,7 +1114,13 @@ type rootModel struct {
 
 	doomRunning bool
 
-	yt ytModel
+	yt   ytModel
+	disc discoverModel
+
+	block keywordList
+	hop   *blockHop
+
+	help string // screen whose help overlay is showing, outside the player
 
 	termW int
 	termH int
@@ -1008,7 +1131,10 @@ func newRootModel() rootModel {
 		active:      0,
 		player:      newModel(),
 		irc:         NewZuseModel(),
+		yt:          ytModel{sceneKind: -1},
+		disc:        newDiscoverModel(),
 		doomRunning: false,
+		block:       newKeywordList(cfg.Blocklist),
 	}
 }
 
@@ -1021,10 +1147,26 @@ func (r *rootModel) switchAudioTo(i int) tea.Cmd {
 	r.player.stopCurrent()
 	r.player.playingIdx = i
 	r.player.startTime = time.Now()
-	return startStreamCmd(i, r.player.ampChan)
+	return startStreamCmd(i)
 }
 
 func (r rootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	r, cmd := r.update(msg)
+	r.syncPainter()
+	return r, cmd
+}
+
+// syncPainter hands the video screen to the painter while it is up (and
+// not covered by help), and takes the terminal back otherwise.
+func (r rootModel) syncPainter() {
+	if r.help == "" && r.yt.painted() {
+		vid.show(r.ytScreen(), r.termW, r.termH)
+		return
+	}
+	vid.hide()
+}
+
+func (r rootModel) update(msg tea.Msg) (rootModel, tea.Cmd) {
 	switch m := msg.(type) {
 
 	case string:
@@ -1034,9 +1176,17 @@ func (r rootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return r, pCmd
 		}
 	case metaAllMsg:
+		pNew, pCmd := r.player.Update(msg)
+		r.player = pNew.(model)
+		bCmd := r.checkBlocklist()
+		return r, tea.Batch(pCmd, bCmd)
+	case listenersMsg, coverMsg, stationsAddedMsg, urlStationMsg:
 		pNew, pCmd := r.player.Update(msg)
 		r.player = pNew.(model)
 		return r, pCmd
+	case discoverResultsMsg:
+		r.disc.setResults(m)
+		return r, nil
 	case streamHandleMsg:
 		pNew, pCmd := r.player.Update(msg)
 		r.player = pNew.(model)
@@ -1052,6 +1202,7 @@ func (r rootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		r.player = pNew.(model)
 		iNew, iCmd := r.irc.Update(msg)
 		r.irc = iNew.(*ZuseModel)
+		r.disc.resize(r.termW, r.termH)
 		if r.yt.active {
 			return r, tea.Batch(pCmd, iCmd, r.yt.restartForSize(r.termW, r.termH))
 		}
@@ -1060,60 +1211,115 @@ func (r rootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		k := m.String()
 
+		if r.help != "" {
+			r.help = ""
+			return r, tea.ClearScreen
+		}
+		if r.disc.active {
+			return r, r.updateDiscover(m)
+		}
+		if r.active == 0 && (r.player.prompting || r.player.fullscreen) {
+			pNew, pCmd := r.player.Update(msg)
+			r.player = pNew.(model)
+			return r, pCmd
+		}
+
 		if r.yt.active {
-			switch k {
-			case "c", "C":
-				r.yt.colorMode = !r.yt.colorMode
+			switch keys.action(scrYouTube, k) {
+			case "color":
+				r.yt.mode = r.yt.mode.next()
+				if r.yt.scene != nil {
+					return r, nil // picked up by the next frame
+				}
 				r.yt.loading = true
 				return r, r.yt.restartForSize(r.termW, r.termH)
-			case "q", "Q":
+			case "scene":
+				r.yt.nextScene()
+				return r, nil
+			case "stats":
+				vid.toggleStats()
+				return r, nil
+			case "dither":
+				l := currentLook()
+				setYTLook((l.dither+1)%ditherCount, l.contrast, l.gamma)
+				return r, nil
+			case "contrast-down", "contrast-up":
+				l := currentLook()
+				step := 0.1
+				if keys.action(scrYouTube, k) == "contrast-down" {
+					step = -step
+				}
+				setYTLook(l.dither, l.contrast+step, l.gamma)
+				return r, nil
+			case "gamma-down", "gamma-up":
+				l := currentLook()
+				step := 0.1
+				if keys.action(scrYouTube, k) == "gamma-down" {
+					step = -step
+				}
+				setYTLook(l.dither, l.contrast, l.gamma+step)
+				return r, nil
+			case "back":
 				r.yt.stop()
 				r.yt.active = false
 				return r, tea.ClearScreen
-			case "left":
+			case "prev-station":
 				newIdx := r.yt.nextIndex(-1)
 				vCmd := r.yt.start(newIdx, max(10, r.termW), max(12, r.termH))
 				aCmd := r.switchAudioTo(newIdx)
 				return r, tea.Batch(vCmd, aCmd)
-			case "right":
+			case "next-station":
 				newIdx := r.yt.nextIndex(+1)
 				vCmd := r.yt.start(newIdx, max(10, r.termW), max(12, r.termH))
 				aCmd := r.switchAudioTo(newIdx)
 				return r, tea.Batch(vCmd, aCmd)
-			case "ctrl+-", "ctrl+_", "ctrl+=", "ctrl+plus", "ctrl+shift+=":
+			case "resize":
 				return r, r.yt.restartForSize(r.termW, r.termH)
+			case "help":
+				r.help = scrYouTube
+				return r, nil
 			default:
 				return r, nil
 			}
 		}
 
-		switch k {
-		case "tab":
+		switch keys.action(scrGlobal, k) {
+		case "next-theme":
+			nextTheme()
+			r.applyTheme()
+			return r, nil
+		case "switch-tab":
 			if r.active == 0 {
 				r.active = 1
 			} else {
 				r.active = 0
 			}
 			return r, nil
-		case "d", "D":
-				// ignore when IRC is active so you can type 'd'
-				if r.active == 1 {
-						break
-				}
+		}
+
+		if r.active == 1 {
+			if r.irc.m.focus == paneServers && keys.action(scrIRCServers, k) == "help" {
+				r.help = scrIRCServers
+				return r, nil
+			}
+		} else {
+			// ignored while IRC is active so you can type these letters
+			switch keys.action(scrPlayer, k) {
+			case "doom":
 				if !r.doomRunning {
-						return r, r.startDoom()
+					return r, r.startDoom()
 				}
 				return r, nil
-		case "y", "Y":
-			if r.active == 1 {
-				break
-			}
-			startIdx := r.player.playingIdx
-			if startIdx < 0 || startIdx >= len(stations) {
-				startIdx = 0
+			case "discover":
+				return r, r.disc.open(r.termW, r.termH)
+			case "youtube":
+				startIdx := r.player.playingIdx
+				if startIdx < 0 || startIdx >= len(stations) {
+					startIdx = 0
+				}
+				r.yt.active = true
+				return r, r.yt.start(startIdx, max(10, r.termW), max(12, r.termH))
 			}
-			r.yt.active = true
-			return r, r.yt.start(startIdx, max(10, r.termW), max(12, r.termH))
 		}
 
 		if r.active == 0 {
@@ -1133,6 +1339,13 @@ func (r rootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return r, nil
 
+	case ytSceneMsg:
+		if m.session != r.yt.session || !r.yt.active || r.yt.scene == nil {
+			return r, nil
+		}
+		r.yt.renderScene(r.player.sceneAudio())
+		return r, sceneTick(m.session)
+
 	case ytStopMsg:
 		if m.session == r.yt.session {
 			r.yt.hasVideo = false
@@ -1167,20 +1380,32 @@ func (r rootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 
+func (r rootModel) ytScreen() string {
+	stationName := ""
+	meta := ""
+	if r.yt.idx >= 0 && r.yt.idx < len(stations) {
+		stationName = stations[r.yt.idx].name
+		meta = stations[r.yt.idx].title
+	}
+	head := "  YouTube ASCII · " + stationName
+	if meta != "" {
+		head += " · " + meta
+	}
+	return fg(th.Header).Render(head) + "\n" + r.yt.View()
+}
+
 func (r rootModel) View() string {
+	if r.help != "" {
+		return fg(th.Accent).Render(keys.helpText(r.help)) + "\n" + fg(th.Dim).Render("  any key to close")
+	}
 	if r.yt.active {
-		stationName := ""
-		meta := ""
-		if r.yt.idx >= 0 && r.yt.idx < len(stations) {
-			stationName = stations[r.yt.idx].name
-			meta = stations[r.yt.idx].title
-		}
-		head := "  YouTube ASCII · " + stationName
-		if meta != "" {
-			head += " · " + meta
+		if r.yt.painted() {
+			return " " // the painter draws it
 		}
-		headStyled := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD75F")).Render(head)
-		return headStyled + "\n" + r.yt.View()
+		return r.ytScreen()
+	}
+	if r.disc.active {
+		return r.disc.View()
 	}
 	if r.active == 0 {
 		return r.player.View()
@@ -1423,6 +1648,20 @@ func hasArg(x string) bool {
 func main() {
 	os.Args[0] = "Nightride Client"
 
+	if len(os.Args) > 1 && (os.Args[1] == "import" || os.Args[1] == "export") {
+		cfg = loadConfig()
+		loadStationCatalog()
+		run := runImport
+		if os.Args[1] == "export" {
+			run = runExport
+		}
+		if err := run(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, os.Args[1]+":", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if hasArg("--doom") {
 		if err := RunDoom(nil); err != nil {
 			fmt.Fprintln(os.Stderr, "doom:", err)
@@ -1440,11 +1679,32 @@ func main() {
 	restoreStderr := redirectStderrToMonitor()
 	defer restoreStderr()
 
-	app = tea.NewProgram(newRootModel(), tea.WithAltScreen())
-	if err := app.Start(); err != nil && err != io.EOF {
+	cfg = loadConfig()
+	keys = newKeymap(cfg.Keys)
+	detectGraphics(cfg.Graphics, cfg.GraphicsTmux)
+	loadArtPacks()
+	loadStationCatalog()
+	loadThemes()
+	startOSC(cfg.OSC)
+
+	root := newRootModel()
+	if !hasArg("--fresh") {
+		if st, ok := loadState(); ok {
+			root.restore(st)
+		}
+	}
+
+	app = tea.NewProgram(root, tea.WithAltScreen(), tea.WithOutput(tty))
+	final, err := app.Run()
+	if err != nil && err != io.EOF {
 		logf("fatal: %v", err)
 		os.Exit(1)
 	}
+	if r, ok := final.(rootModel); ok {
+		if err := saveState(r.sessionState()); err != nil {
+			logf("state: %v", err)
+		}
+	}
 }
 
 var _ = image.Rect
@@ -1452,7 +1712,8 @@ var _ = color.RGBA{}
 
 /* ───────────── ffmpeg process helpers (shared) ───────────── */
 
-func startFFMPEG(hls string, cols, asciiRows int) (*exec.Cmd, io.ReadCloser, error) {
+// startFFMPEG decodes the stream to raw rgb24 frames of w x h pixels.
+func startFFMPEG(hls string, w, h int) (*exec.Cmd, io.ReadCloser, error) {
 	args := []string{
 			"-hide_banner", "-loglevel", "error",
 			"-fflags", "nobuffer",
@@ -1462,7 +1723,7 @@ func startFFMPEG(hls string, cols, asciiRows int) (*exec.Cmd, io.ReadCloser, err
 			"-thread_queue_size", "512",
 			"-i", hls,
 			"-an",
-			"-vf", fmt.Sprintf("scale=%d:%d:flags=fast_bilinear,fps=20", cols, asciiRows),
+			"-vf", fmt.Sprintf("scale=%d:%d:flags=fast_bilinear,fps=20", w, h),
 			"-pix_fmt", "rgb24",
 			"-f", "rawvideo", "pipe:1",
 	}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+/* ───────────── OSC output ───────────── */
+
+// oscConfig turns on a UDP feed of the visualizer for lighting and VJ rigs.
+// It is off unless a port is set. Each tick sends:
+//
+//	/nightride/bands  f…      one 0..1 value per spectrum column
+//	/nightride/beat   i f     1 if an onset landed since the last tick, BPM
+//	/nightride/title  s s     station, track
+type oscConfig struct {
+	Host string  `json:"host"` // default 127.0.0.1
+	Port int     `json:"port"`
+	Rate float64 `json:"rate"` // messages per second, default 30
+}
+
+// oscTitle is the station and track the OSC feed reports; the player
+// refreshes it on its scroll tick.
+var oscTitle atomic.Pointer[[2]string]
+
+func setOSCTitle(station, track string) {
+	if t := oscTitle.Load(); t == nil || t[0] != station || t[1] != track {
+		oscTitle.Store(&[2]string{station, track})
+	}
+}
+
+func startOSC(c oscConfig) {
+	if c.Port <= 0 {
+		return
+	}
+	host := c.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	rate := c.Rate
+	if rate <= 0 {
+		rate = 30
+	}
+	addr := net.JoinHostPort(host, fmt.Sprint(c.Port))
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		logf("osc %s: %v", addr, err)
+		return
+	}
+	logf("osc: sending to %s at %.0f Hz", addr, rate)
+	go oscLoop(conn, time.Duration(float64(time.Second)/rate))
+}
+
+func oscLoop(conn net.Conn, every time.Duration) {
+	var (
+		f         vizFrame
+		seq       uint64
+		beatsSent uint64
+		peak      = 1e-6
+		norm      []float64
+		msg       oscMessage
+		failing   bool
+		fresh     time.Time
+	)
+	send := func() {
+		if _, err := conn.Write(msg.buf); err != nil && !failing {
+			logf("osc: %v", err) // usually nothing listening yet; log once
+			failing = true
+		} else if err == nil {
+			failing = false
+		}
+	}
+	for range time.Tick(every) {
+		if latestViz(&f, &seq) {
+			fresh = time.Now()
+		} else if time.Since(fresh) > 250*time.Millisecond {
+			clear(f.bands) // stopped; let the strip go dark
+		}
+
+		// same auto-gain the bars use, so quiet tracks still fill the strip
+		peak = math.Max(peak*0.93, 1e-6)
+		for _, a := range f.bands {
+			peak = math.Max(peak, a)
+		}
+		norm = resized(norm, len(f.bands))
+		for i, a := range f.bands {
+			norm[i] = math.Min(1, a/peak)
+		}
+		msg.start("/nightride/bands", len(norm))
+		for _, v := range norm {
+			msg.float(v)
+		}
+		send()
+
+		beat := int32(0)
+		if f.beats != beatsSent {
+			beat, beatsSent = 1, f.beats
+		}
+		msg.start("/nightride/beat", 2)
+		msg.int(beat)
+		msg.float(f.bpm)
+		send()
+
+		if t := oscTitle.Load(); t != nil {
+			msg.start("/nightride/title", 2)
+			msg.str(t[0])
+			msg.str(t[1])
+			send()
+		}
+	}
+}
+
+// oscMessage builds one OSC 1.0 message in a reused buffer. start writes
+// the address and reserves the type tags; each argument then fills in its
+// tag and appends its data.
+type oscMessage struct {
+	buf []byte
+	tag int // next type tag byte
+}
+
+func (m *oscMessage) start(addr string, nargs int) {
+	m.buf = oscPad(append(m.buf[:0], addr...))
+	m.tag = len(m.buf) + 1
+	m.buf = append(m.buf, ',')
+	for i := 0; i < nargs; i++ {
+		m.buf = append(m.buf, 0)
+	}
+	m.buf = oscPad(m.buf)
+}
+
+func (m *oscMessage) float(v float64) {
+	m.buf[m.tag] = 'f'
+	m.tag++
+	m.buf = binary.BigEndian.AppendUint32(m.buf, math.Float32bits(float32(v)))
+}
+
+func (m *oscMessage) int(v int32) {
+	m.buf[m.tag] = 'i'
+	m.tag++
+	m.buf = binary.BigEndian.AppendUint32(m.buf, uint32(v))
+}
+
+func (m *oscMessage) str(s string) {
+	m.buf[m.tag] = 's'
+	m.tag++
+	m.buf = oscPad(append(m.buf, s...))
+}
+
+// oscPad null-terminates b and pads it to a multiple of four bytes.
+func oscPad(b []byte) []byte {
+	b = append(b, 0)
+	for len(b)%4 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
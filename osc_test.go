@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// oscDecode splits one OSC message into its address, type tags and
+// arguments, failing on anything that isn't padded to four bytes.
+func oscDecode(t *testing.T, p []byte) (addr, tags string, args []any) {
+	t.Helper()
+	if len(p)%4 != 0 {
+		t.Fatalf("packet is %d bytes, not a multiple of 4: %q", len(p), p)
+	}
+	str := func() string {
+		i := bytes.IndexByte(p, 0)
+		if i < 0 {
+			t.Fatalf("unterminated string in %q", p)
+		}
+		s := string(p[:i])
+		n := (i + 4) &^ 3
+		if n > len(p) || !bytes.Equal(p[i:n], make([]byte, n-i)) {
+			t.Fatalf("string %q not null-padded to 4 bytes", s)
+		}
+		p = p[n:]
+		return s
+	}
+	addr, tags = str(), str()
+	if tags == "" || tags[0] != ',' {
+		t.Fatalf("%s: type tags %q don't start with ','", addr, tags)
+	}
+	for _, tag := range tags[1:] {
+		switch tag {
+		case 'f':
+			args = append(args, math.Float32frombits(binary.BigEndian.Uint32(p)))
+			p = p[4:]
+		case 'i':
+			args = append(args, int32(binary.BigEndian.Uint32(p)))
+			p = p[4:]
+		case 's':
+			args = append(args, str())
+		default:
+			t.Fatalf("%s: unknown type tag %q", addr, tag)
+		}
+	}
+	if len(p) != 0 {
+		t.Fatalf("%s: %d trailing bytes", addr, len(p))
+	}
+	return addr, tags, args
+}
+
+func TestOSCFeed(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	setOSCTitle("Nightride FM", "Perturbator - Venger")
+	startOSC(oscConfig{Port: pc.LocalAddr().(*net.UDPAddr).Port, Rate: 100})
+
+	want := map[string]struct {
+		tags string
+		args []any
+	}{
+		"/nightride/beat":  {",if", []any{int32(0), float32(0)}},
+		"/nightride/title": {",ss", []any{"Nightride FM", "Perturbator - Venger"}},
+	}
+	seen := map[string]bool{}
+	buf := make([]byte, 2048)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for len(seen) < 3 {
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("after %v: %v", seen, err)
+		}
+		addr, tags, args := oscDecode(t, buf[:n])
+		seen[addr] = true
+		if addr == "/nightride/bands" {
+			for _, a := range args {
+				if _, ok := a.(float32); !ok {
+					t.Errorf("bands: %v is not a float", a)
+				}
+			}
+			continue
+		}
+		w, ok := want[addr]
+		if !ok {
+			t.Fatalf("unexpected address %q", addr)
+		}
+		if tags != w.tags || !reflect.DeepEqual(args, w.args) {
+			t.Errorf("%s: got %s %v, want %s %v", addr, tags, args, w.tags, w.args)
+		}
+	}
+}
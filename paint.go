@@ -0,0 +1,462 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+/* ───────────── video painter ───────────── */
+
+// Bubble Tea repaints every line that changed, whole, and a video frame
+// changes every line; at 20fps over SSH that is most of the link. While a
+// text-mode video or scene is up, the View is a single blank line and the
+// painter owns the screen instead: it keeps the cells it last drew, writes
+// only the ones that changed with cursor moves in between, and drops
+// frames when the terminal can't take them that fast.
+
+const (
+	paintMinFPS = 2
+	paintMaxFPS = 30 // scenes tick at 30, ffmpeg sends 20
+)
+
+// ttyOut is the program's stdout. Bubble Tea and the painter both write
+// through it, so writes never interleave, and the time spent blocked in
+// them says how fast the terminal (or the SSH link behind it) drains.
+type ttyOut struct {
+	f    *os.File
+	mu   sync.Mutex
+	n    int64
+	busy time.Duration
+}
+
+var tty = &ttyOut{f: os.Stdout}
+
+func (t *ttyOut) Read(p []byte) (int, error) { return t.f.Read(p) }
+func (t *ttyOut) Close() error               { return t.f.Close() }
+func (t *ttyOut) Fd() uintptr                { return t.f.Fd() }
+
+// Write is Bubble Tea's path. Whatever it draws while the painter owns the
+// screen may cover the video, so the painter redraws everything next time.
+func (t *ttyOut) Write(p []byte) (int, error) {
+	n, err := t.write(p)
+	vid.touched()
+	return n, err
+}
+
+func (t *ttyOut) write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	start := time.Now()
+	n, err := t.f.Write(p)
+	t.busy += time.Since(start)
+	t.n += int64(n)
+	return n, err
+}
+
+func (t *ttyOut) counters() (int64, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.n, t.busy
+}
+
+// cellStyle is a cell's SGR state; colors are kept as written
+// ("38;5;18", "91"), "" being the default.
+type cellStyle struct {
+	fg, bg string
+	attrs  uint16 // bit n set for SGR n, 1 (bold) to 9 (strike)
+}
+
+// cell is one terminal cell. The right half of a wide rune has ch "".
+type cell struct {
+	ch string
+	st cellStyle
+}
+
+type painter struct {
+	mu     sync.Mutex
+	on     bool
+	owns   atomic.Bool // on, readable without the lock
+	stale  atomic.Bool
+	screen string // latest View of the video screen
+	w, h   int
+	prev   []cell // what the terminal shows; nil when unknown
+	cur    []cell
+	buf    []byte
+	stats  bool
+	wake   chan struct{}
+	once   sync.Once
+
+	fps      float64
+	next     time.Time
+	frameB   float64 // bytes per paint, smoothed
+	rate     float64 // bytes per second written, last window
+	winStart time.Time
+	winN     int64
+	winBusy  time.Duration
+}
+
+var vid = &painter{wake: make(chan struct{}, 1), fps: paintMaxFPS}
+
+// show hands the painter the video screen's latest View. Called from
+// Update, so View stays free of side effects.
+func (p *painter) show(screen string, w, h int) {
+	p.once.Do(func() { go p.loop() })
+	p.mu.Lock()
+	if !p.on {
+		p.on, p.prev = true, nil
+		p.owns.Store(true)
+	}
+	p.screen, p.w, p.h = screen, w, h
+	p.mu.Unlock()
+	p.nudge()
+}
+
+// hide gives the screen back to Bubble Tea. Once it returns, no paint is
+// in flight, so nothing lands on top of what Bubble Tea draws next. The
+// video is cleared too: Bubble Tea last drew a single line, so it only
+// rewrites the lines of its next View and would leave the rest behind.
+func (p *painter) hide() {
+	if !p.owns.Load() {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.on, p.prev = false, nil
+	p.owns.Store(false)
+	if _, err := tty.write([]byte("\x1b[2J")); err != nil {
+		logf("paint: %v", err)
+	}
+}
+
+func (p *painter) toggleStats() {
+	p.mu.Lock()
+	p.stats = !p.stats
+	p.mu.Unlock()
+	p.nudge()
+}
+
+func (p *painter) touched() {
+	if p.owns.Load() {
+		p.stale.Store(true)
+		p.nudge()
+	}
+}
+
+func (p *painter) nudge() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// loop paints at most fps times a second; screens that arrive while it
+// waits replace each other, so a slow terminal just sees fewer frames.
+func (p *painter) loop() {
+	for range p.wake {
+		p.mu.Lock()
+		wait := time.Until(p.next)
+		p.mu.Unlock()
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+		p.paint()
+	}
+}
+
+func (p *painter) paint() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.on || p.w <= 0 || p.h <= 0 {
+		return
+	}
+	n := p.w * p.h
+	if p.stale.Swap(false) || len(p.prev) != n {
+		p.prev = nil
+	}
+	p.cur = resized(p.cur, n)
+	parseScreen(p.cur, p.screen, p.w, p.h)
+	if p.stats {
+		p.overlay()
+	}
+	p.buf = diffCells(p.buf[:0], p.prev, p.cur, p.w, p.h)
+	if len(p.buf) == 0 {
+		return
+	}
+	if _, err := tty.write(p.buf); err != nil {
+		logf("paint: %v", err)
+	}
+	p.prev, p.cur = p.cur, p.prev
+	p.account(len(p.buf))
+}
+
+// account paces the next paint. Once a second it looks at how long
+// writes blocked: past half the time the terminal is the bottleneck, and
+// fps drops to what its measured throughput carries at the current frame
+// size; under a fifth it creeps back up.
+func (p *painter) account(n int) {
+	now := time.Now()
+	if p.frameB == 0 {
+		p.frameB = float64(n)
+	}
+	p.frameB += (float64(n) - p.frameB) * 0.2
+	total, busy := tty.counters()
+	if p.winStart.IsZero() {
+		p.winStart, p.winN, p.winBusy = now, total, busy
+	} else if el := now.Sub(p.winStart); el >= time.Second {
+		dn, db := float64(total-p.winN), busy-p.winBusy
+		p.rate = dn / el.Seconds()
+		switch load := db.Seconds() / el.Seconds(); {
+		case load > 0.5:
+			fit := dn / db.Seconds() / p.frameB * 0.5
+			p.fps = math.Max(paintMinFPS, math.Min(p.fps*0.8, fit))
+		case load < 0.2:
+			p.fps = math.Min(paintMaxFPS, p.fps+2)
+		}
+		p.winStart, p.winN, p.winBusy = now, total, busy
+	}
+	p.next = now.Add(time.Duration(float64(time.Second) / p.fps))
+}
+
+// overlay writes the stats into the top-right corner of the screen.
+func (p *painter) overlay() {
+	s := fmt.Sprintf(" %s/frame · %.0f fps · %s/s ", sizeLabel(p.frameB), p.fps, sizeLabel(p.rate))
+	x := max(0, p.w-utf8.RuneCountInString(s))
+	st := cellStyle{attrs: 1 << 7}
+	for i, r := range s {
+		if x >= p.w {
+			break
+		}
+		p.cur[x] = cell{ch: s[i : i+utf8.RuneLen(r)], st: st}
+		x++
+	}
+}
+
+func sizeLabel(b float64) string {
+	if b < 1024 {
+		return fmt.Sprintf("%.0f B", b)
+	}
+	return fmt.Sprintf("%.1f KB", b/1024)
+}
+
+// parseScreen lays a rendered View out into w x h cells, keeping SGR
+// state across lines the way the terminal would.
+func parseScreen(cells []cell, s string, w, h int) {
+	for i := range cells {
+		cells[i] = cell{ch: " "}
+	}
+	var st cellStyle
+	x, y := 0, 0
+	for i := 0; i < len(s) && y < h; {
+		switch c := s[i]; {
+		case c == '\n':
+			x, y = 0, y+1
+			i++
+		case c == '\x1b':
+			i = parseEscape(s, i, &st)
+		case c < ' ':
+			i++
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			rw := runewidth.RuneWidth(r)
+			if rw > 0 && x+rw <= w {
+				cells[y*w+x] = cell{ch: s[i : i+size], st: st}
+				if rw == 2 {
+					cells[y*w+x+1] = cell{st: st}
+				}
+			}
+			x += rw
+			i += size
+		}
+	}
+}
+
+// parseEscape applies an SGR sequence to st and skips over anything else,
+// returning the index after the sequence.
+func parseEscape(s string, i int, st *cellStyle) int {
+	if i+1 >= len(s) {
+		return len(s)
+	}
+	switch s[i+1] {
+	case '[':
+		j := i + 2
+		for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+			j++
+		}
+		if j < len(s) && s[j] == 'm' {
+			st.apply(s[i+2 : j])
+		}
+		return j + 1
+	case ']', 'P', '_', '^', 'X':
+		// string sequences (OSC, DCS, APC…) end at BEL or ST
+		for j := i + 2; j < len(s); j++ {
+			if s[j] == '\a' {
+				return j + 1
+			}
+			if s[j] == '\x1b' && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2
+			}
+		}
+		return len(s)
+	}
+	return i + 2
+}
+
+func (st *cellStyle) apply(params string) {
+	if params == "" {
+		*st = cellStyle{}
+		return
+	}
+	for i := 0; i < len(params); {
+		end := fieldEnd(params, i)
+		n, _ := strconv.Atoi(params[i:end])
+		switch {
+		case n == 0:
+			*st = cellStyle{}
+		case n >= 1 && n <= 9:
+			st.attrs |= 1 << n
+		case n == 22:
+			st.attrs &^= 1<<1 | 1<<2
+		case n >= 23 && n <= 29:
+			st.attrs &^= 1 << (n - 20)
+		case n >= 30 && n <= 37, n >= 90 && n <= 97:
+			st.fg = params[i:end]
+		case n == 39:
+			st.fg = ""
+		case n >= 40 && n <= 47, n >= 100 && n <= 107:
+			st.bg = params[i:end]
+		case n == 49:
+			st.bg = ""
+		case n == 38 || n == 48:
+			// 38;5;n or 38;2;r;g;b, kept whole
+			fields := 2
+			if strings.HasPrefix(params[min(end+1, len(params)):], "2;") {
+				fields = 4
+			}
+			for k := 0; k < fields && end < len(params); k++ {
+				end = fieldEnd(params, end+1)
+			}
+			if n == 38 {
+				st.fg = params[i:end]
+			} else {
+				st.bg = params[i:end]
+			}
+		}
+		i = end + 1
+	}
+}
+
+func fieldEnd(s string, i int) int {
+	if j := strings.IndexByte(s[i:], ';'); j >= 0 {
+		return i + j
+	}
+	return len(s)
+}
+
+// diffCells appends what turns prev into cur on screen; with prev nil,
+// every cell. Output is wrapped in a synchronized update and restores the
+// cursor and attributes Bubble Tea left behind.
+func diffCells(buf []byte, prev, cur []cell, w, h int) []byte {
+	cx, cy := -1, -1
+	var pen cellStyle
+	started := false
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			c := cur[i]
+			if c.ch == "" {
+				continue
+			}
+			wide := x+1 < w && cur[i+1].ch == ""
+			if prev != nil && prev[i] == c && (!wide || prev[i+1] == cur[i+1]) {
+				continue
+			}
+			if !started {
+				buf = append(buf, "\x1b[?2026h\x1b7\x1b[0m"...)
+				started = true
+			}
+			switch {
+			case cy == y && cx == x:
+			case cy == y && x > cx && x-cx <= 3 && sameStyle(cur[y*w+cx:i], pen):
+				// a short gap is cheaper to write again than to skip
+				for _, s := range cur[y*w+cx : i] {
+					buf = append(buf, s.ch...)
+				}
+			case cy == y && x > cx:
+				buf = append(buf, "\x1b["...)
+				buf = strconv.AppendInt(buf, int64(x-cx), 10)
+				buf = append(buf, 'C')
+			default:
+				buf = append(buf, "\x1b["...)
+				buf = strconv.AppendInt(buf, int64(y+1), 10)
+				buf = append(buf, ';')
+				buf = strconv.AppendInt(buf, int64(x+1), 10)
+				buf = append(buf, 'H')
+			}
+			buf = pen.to(buf, c.st)
+			pen = c.st
+			buf = append(buf, c.ch...)
+			cx, cy = x+1, y
+			if wide {
+				cx++
+			}
+			if cx >= w {
+				cy = -1 // wrap pending; position explicitly next time
+			}
+		}
+	}
+	if started {
+		buf = append(buf, "\x1b[0m\x1b8\x1b[?2026l"...)
+	}
+	return buf
+}
+
+func sameStyle(cells []cell, st cellStyle) bool {
+	for _, c := range cells {
+		if c.st != st {
+			return false
+		}
+	}
+	return true
+}
+
+// to appends the SGR sequence that switches from a to b.
+func (a cellStyle) to(buf []byte, b cellStyle) []byte {
+	if a == b {
+		return buf
+	}
+	buf = append(buf, "\x1b["...)
+	sep := false
+	param := func(s string) {
+		if sep {
+			buf = append(buf, ';')
+		}
+		buf = append(buf, s...)
+		sep = true
+	}
+	if a.attrs&^b.attrs != 0 {
+		param("0")
+		a = cellStyle{}
+	}
+	for n := 1; n <= 9; n++ {
+		if b.attrs&^a.attrs&(1<<n) != 0 {
+			param(strconv.Itoa(n))
+		}
+	}
+	if b.fg != a.fg {
+		param(cmp.Or(b.fg, "39"))
+	}
+	if b.bg != a.bg {
+		param(cmp.Or(b.bg, "49"))
+	}
+	return append(buf, 'm')
+}
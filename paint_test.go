@@ -0,0 +1,172 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCellStyleApply(t *testing.T) {
+	const bold, faint, italic, under, reverse, strike = 1 << 1, 1 << 2, 1 << 3, 1 << 4, 1 << 7, 1 << 9
+	tests := []struct {
+		from   cellStyle
+		params string
+		want   cellStyle
+	}{
+		{cellStyle{}, "38;5;18", cellStyle{fg: "38;5;18"}},
+		{cellStyle{}, "48;5;232", cellStyle{bg: "48;5;232"}},
+		{cellStyle{}, "38;2;1;2;3", cellStyle{fg: "38;2;1;2;3"}},
+		{cellStyle{}, "48;2;10;20;30;1", cellStyle{bg: "48;2;10;20;30", attrs: bold}},
+		{cellStyle{}, "1;38;5;18;48;2;0;0;0;4", cellStyle{fg: "38;5;18", bg: "48;2;0;0;0", attrs: bold | under}},
+		{cellStyle{}, "91;41", cellStyle{fg: "91", bg: "41"}},
+		{cellStyle{fg: "31", bg: "41"}, "39", cellStyle{bg: "41"}},
+		{cellStyle{fg: "31", bg: "41"}, "49", cellStyle{fg: "31"}},
+		{cellStyle{fg: "31", attrs: bold}, "0", cellStyle{}},
+		{cellStyle{fg: "31", attrs: bold}, "", cellStyle{}},
+		{cellStyle{attrs: bold | faint | italic}, "22", cellStyle{attrs: italic}},
+		{cellStyle{attrs: bold | italic}, "23", cellStyle{attrs: bold}},
+		{cellStyle{attrs: under | reverse}, "24", cellStyle{attrs: reverse}},
+		{cellStyle{attrs: under | reverse}, "27", cellStyle{attrs: under}},
+		{cellStyle{attrs: bold | strike}, "29", cellStyle{attrs: bold}},
+	}
+	for _, tt := range tests {
+		st := tt.from
+		st.apply(tt.params)
+		if st != tt.want {
+			t.Errorf("%+v + %q: got %+v, want %+v", tt.from, tt.params, st, tt.want)
+		}
+	}
+}
+
+// rows renders cells back to plain text, one string per row, with the
+// right half of a wide rune shown as "_".
+func rows(cells []cell, w int) []string {
+	var out []string
+	for y := 0; y*w < len(cells); y++ {
+		var b strings.Builder
+		for _, c := range cells[y*w : (y+1)*w] {
+			if c.ch == "" {
+				b.WriteByte('_')
+			} else {
+				b.WriteString(c.ch)
+			}
+		}
+		out = append(out, b.String())
+	}
+	return out
+}
+
+func TestParseScreen(t *testing.T) {
+	red := cellStyle{fg: "31"}
+	tests := []struct {
+		name   string
+		screen string
+		w, h   int
+		want   []string
+		styles map[int]cellStyle // cell index -> style, others default
+	}{
+		{
+			name:   "style carries over lines, OSC skipped",
+			screen: "\x1b[31mab\x1b]8;;http://x\x1b\\c\nd\x1b[0me",
+			w:      4, h: 2,
+			want:   []string{"abc ", "de  "},
+			styles: map[int]cellStyle{0: red, 1: red, 2: red, 4: red},
+		},
+		{
+			name:   "wide runes",
+			screen: "漢字\na漢",
+			w:      4, h: 2,
+			want: []string{"漢_字_", "a漢_ "},
+		},
+		{
+			name:   "wide rune at the right edge is dropped",
+			screen: "ab漢\nabc漢",
+			w:      3, h: 2,
+			want: []string{"ab ", "abc"},
+		},
+		{
+			name:   "clipped to the screen",
+			screen: "abcdef\n1\n2\n3",
+			w:      3, h: 2,
+			want: []string{"abc", "1  "},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := make([]cell, tt.w*tt.h)
+			parseScreen(cells, tt.screen, tt.w, tt.h)
+			if got := rows(cells, tt.w); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			for i, c := range cells {
+				if c.st != tt.styles[i] {
+					t.Errorf("cell %d: style %+v, want %+v", i, c.st, tt.styles[i])
+				}
+			}
+		})
+	}
+}
+
+func screenCells(s string, w, h int) []cell {
+	cells := make([]cell, w*h)
+	parseScreen(cells, s, w, h)
+	return cells
+}
+
+func TestDiffCells(t *testing.T) {
+	const start, end = "\x1b[?2026h\x1b7\x1b[0m", "\x1b[0m\x1b8\x1b[?2026l"
+	tests := []struct {
+		name      string
+		prev, cur string // "" prev means unknown (nil)
+		w, h      int
+		want      string
+	}{
+		{"nil prev repaints everything", "", "abc\nde", 3, 2,
+			start + "\x1b[1;1Habc\x1b[2;1Hde " + end},
+		{"no change writes nothing", "abc\nde", "abc\nde", 3, 2, ""},
+		{"one cell", "abc\ndef", "abc\ndXf", 3, 2,
+			start + "\x1b[2;2HX" + end},
+		{"short gap is filled", "abcdefgh", "AbcDefgh", 8, 1,
+			start + "\x1b[1;1HAbcD" + end},
+		{"long gap is skipped", "abcdefgh", "AbcdefGh", 8, 1,
+			start + "\x1b[1;1HA\x1b[5CG" + end},
+		{"gap in another style is skipped", "a\x1b[31mbc\x1b[0mdefgh", "A\x1b[31mbc\x1b[0mDefgh", 8, 1,
+			start + "\x1b[1;1HA\x1b[2CD" + end},
+		{"style changes", "abc", "a\x1b[1;31mb\x1b[0mc", 3, 1,
+			start + "\x1b[1;2H\x1b[1;31mb" + end},
+		{"wide rune redrawn whole", "a漢b", "a字b", 4, 1,
+			start + "\x1b[1;2H字" + end},
+		{"cursor after a full row is set again", "ab\ncd", "aX\nYd", 2, 2,
+			start + "\x1b[1;2HX\x1b[2;1HY" + end},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prev []cell
+			if tt.prev != "" {
+				prev = screenCells(tt.prev, tt.w, tt.h)
+			}
+			got := string(diffCells(nil, prev, screenCells(tt.cur, tt.w, tt.h), tt.w, tt.h))
+			if got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCellStyleTo(t *testing.T) {
+	tests := []struct {
+		a, b cellStyle
+		want string
+	}{
+		{cellStyle{}, cellStyle{}, ""},
+		{cellStyle{}, cellStyle{fg: "91", attrs: 1 << 1}, "\x1b[1;91m"},
+		{cellStyle{fg: "91"}, cellStyle{}, "\x1b[39m"},
+		{cellStyle{bg: "48;5;18"}, cellStyle{bg: "48;2;1;2;3"}, "\x1b[48;2;1;2;3m"},
+		// attributes can only be dropped by a reset, which also drops colors
+		{cellStyle{fg: "31", bg: "41", attrs: 1 << 1}, cellStyle{fg: "31"}, "\x1b[0;31m"},
+	}
+	for _, tt := range tests {
+		if got := string(tt.a.to(nil, tt.b)); got != tt.want {
+			t.Errorf("%+v -> %+v: got %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
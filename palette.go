@@ -0,0 +1,131 @@
+package main
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/muesli/termenv"
+)
+
+/* ───────────── color depth ───────────── */
+
+// The video, cover and DOOM converters work in 24-bit color. Terminals
+// that only do 256 or 16 colors (and SSH sessions, where COLORTERM rarely
+// survives) get each cell mapped to the nearest palette entry instead, and
+// every converter skips the escape when a cell's color matches the last.
+
+// pen is a quantized color: a palette index, or 24-bit RGB with penRGB set.
+type pen uint32
+
+const (
+	penRGB  pen = 1 << 24
+	penNone pen = 1 << 25 // nothing written yet
+)
+
+// penProfile is the depth colors are quantized to. NO_COLOR means none;
+// otherwise no profile at all means output isn't a terminal, so keep full
+// color like before.
+func penProfile() termenv.Profile {
+	switch {
+	case noColor:
+		return termenv.Ascii
+	case baseProfile == termenv.Ascii:
+		return termenv.TrueColor
+	}
+	return baseProfile
+}
+
+// penFor quantizes a color to the terminal's depth. Under NO_COLOR every
+// cell gets penNone, which callers already treat as nothing to write.
+func penFor(r, g, b byte) pen {
+	switch penProfile() {
+	case termenv.Ascii:
+		return penNone
+	case termenv.ANSI:
+		return pen(nearest16(float64(r), float64(g), float64(b)))
+	case termenv.ANSI256:
+		return pen(nearest256(r, g, b))
+	}
+	return penRGB | pen(r)<<16 | pen(g)<<8 | pen(b)
+}
+
+// appendPen writes p's SGR parameters, as foreground or background.
+func appendPen(buf []byte, p pen, bg bool) []byte {
+	switch {
+	case p&penRGB != 0:
+		if bg {
+			buf = append(buf, "48;2;"...)
+		} else {
+			buf = append(buf, "38;2;"...)
+		}
+		return appendRGB(buf, byte(p>>16), byte(p>>8), byte(p))
+	case penProfile() == termenv.ANSI:
+		base := 30
+		if p >= 8 {
+			base, p = 90, p-8
+		}
+		if bg {
+			base += 10
+		}
+		return strconv.AppendInt(buf, int64(base)+int64(p), 10)
+	}
+	if bg {
+		buf = append(buf, "48;5;"...)
+	} else {
+		buf = append(buf, "38;5;"...)
+	}
+	return strconv.AppendInt(buf, int64(p), 10)
+}
+
+// cube256 holds the levels of xterm's 6x6x6 color cube.
+var cube256 = [6]int{0, 95, 135, 175, 215, 255}
+
+func cubeLevel(v byte) int {
+	switch {
+	case v < 48:
+		return 0
+	case v < 115:
+		return 1
+	}
+	return (int(v) - 35) / 40
+}
+
+// nearest256 picks the closer of the nearest cube color and the nearest
+// step of the gray ramp (232–255), which has finer shades than the cube.
+func nearest256(r, g, b byte) int {
+	cr, cg, cb := cubeLevel(r), cubeLevel(g), cubeLevel(b)
+	cube := 16 + 36*cr + 6*cg + cb
+	cd := dist3(r, g, b, cube256[cr], cube256[cg], cube256[cb])
+
+	avg := (int(r) + int(g) + int(b)) / 3
+	gi := min(23, max(0, (avg-3)/10))
+	gv := 8 + 10*gi
+	if dist3(r, g, b, gv, gv, gv) < cd {
+		return 232 + gi
+	}
+	return cube
+}
+
+func dist3(r, g, b byte, r2, g2, b2 int) int {
+	dr, dg, db := int(r)-r2, int(g)-g2, int(b)-b2
+	return dr*dr*3 + dg*dg*6 + db*db
+}
+
+// ansi16 is xterm's default 16-color palette.
+var ansi16 = [16][3]float64{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+func nearest16(r, g, b float64) int {
+	best, bestD := 0, math.Inf(1)
+	for i, c := range ansi16 {
+		dr, dg, db := r-c[0], g-c[1], b-c[2]
+		if d := dr*dr*0.3 + dg*dg*0.59 + db*db*0.11; d < bestD {
+			best, bestD = i, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNoColorWritesNoColors(t *testing.T) {
+	defer func(v bool) { noColor = v }(noColor)
+	noColor = true
+	rgb := gradient(16, 4)
+	for i := range rgb {
+		rgb[i] ^= byte(i * 37) // some hue, not just grays
+	}
+	if out := fastColorASCII(rgb, 16, 4); strings.Contains(out, "38;") || strings.Contains(out, "48;") {
+		t.Errorf("NO_COLOR frame has color escapes: %q", out)
+	}
+	if p := penFor(255, 0, 0); p != penNone {
+		t.Errorf("penFor under NO_COLOR = %#x, want penNone", p)
+	}
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/* ───────────── M3U / PLS / XSPF playlists ───────────── */
+
+type playlistEntry struct {
+	title string
+	url   string
+}
+
+func playlistKind(name string) string {
+	if u, err := url.Parse(name); err == nil && u.Scheme != "" {
+		name = u.Path
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".m3u":
+		return "m3u"
+	case ".pls":
+		return "pls"
+	case ".xspf":
+		return "xspf"
+	}
+	return ""
+}
+
+// parsePlaylist reads any of the three formats. kind may be empty, in
+// which case the content decides.
+func parsePlaylist(kind string, data []byte) ([]playlistEntry, error) {
+	if kind == "" {
+		t := bytes.TrimSpace(data)
+		switch {
+		case bytes.HasPrefix(t, []byte("[playlist]")):
+			kind = "pls"
+		case bytes.HasPrefix(t, []byte("<?xml")), bytes.HasPrefix(t, []byte("<playlist")):
+			kind = "xspf"
+		default:
+			kind = "m3u"
+		}
+	}
+	var (
+		out []playlistEntry
+		err error
+	)
+	switch kind {
+	case "pls":
+		out, err = parsePLS(data)
+	case "xspf":
+		out, err = parseXSPF(data)
+	default:
+		out, err = parseM3U(data)
+	}
+	if err == nil && len(out) == 0 {
+		err = errors.New("playlist has no stream entries")
+	}
+	return out, err
+}
+
+func parseM3U(data []byte) ([]playlistEntry, error) {
+	var (
+		out   []playlistEntry
+		title string
+	)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(sc.Text(), "\ufeff"))
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#EXTINF:"):
+			if i := strings.IndexByte(line, ','); i >= 0 {
+				title = strings.TrimSpace(line[i+1:])
+			}
+		case strings.HasPrefix(line, "#"):
+		default:
+			out = append(out, playlistEntry{title: title, url: line})
+			title = ""
+		}
+	}
+	return out, sc.Err()
+}
+
+func parsePLS(data []byte) ([]playlistEntry, error) {
+	files := map[int]*playlistEntry{}
+	get := func(n int) *playlistEntry {
+		if files[n] == nil {
+			files[n] = &playlistEntry{}
+		}
+		return files[n]
+	}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		k, v, ok := strings.Cut(strings.TrimSpace(sc.Text()), "=")
+		if !ok {
+			continue
+		}
+		lk := strings.ToLower(strings.TrimSpace(k))
+		v = strings.TrimSpace(v)
+		switch {
+		case strings.HasPrefix(lk, "file"):
+			if n, err := strconv.Atoi(lk[4:]); err == nil {
+				get(n).url = v
+			}
+		case strings.HasPrefix(lk, "title"):
+			if n, err := strconv.Atoi(lk[5:]); err == nil {
+				get(n).title = v
+			}
+		}
+	}
+	nums := make([]int, 0, len(files))
+	for n, e := range files {
+		if e.url != "" {
+			nums = append(nums, n)
+		}
+	}
+	sort.Ints(nums)
+	out := make([]playlistEntry, 0, len(nums))
+	for _, n := range nums {
+		out = append(out, *files[n])
+	}
+	return out, sc.Err()
+}
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Version string      `xml:"version,attr"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location"`
+	Title    string `xml:"title,omitempty"`
+}
+
+func parseXSPF(data []byte) ([]playlistEntry, error) {
+	var pl xspfPlaylist
+	if err := xml.Unmarshal(data, &pl); err != nil {
+		return nil, err
+	}
+	var out []playlistEntry
+	for _, t := range pl.Tracks {
+		if loc := strings.TrimSpace(t.Location); loc != "" {
+			out = append(out, playlistEntry{title: strings.TrimSpace(t.Title), url: loc})
+		}
+	}
+	return out, nil
+}
+
+func writePlaylist(w io.Writer, kind string, list []station) error {
+	switch kind {
+	case "m3u":
+		fmt.Fprintln(w, "#EXTM3U")
+		for _, st := range list {
+			fmt.Fprintf(w, "#EXTINF:-1,%s\n%s\n", st.name, st.url)
+		}
+	case "pls":
+		fmt.Fprintln(w, "[playlist]")
+		for i, st := range list {
+			fmt.Fprintf(w, "File%d=%s\nTitle%d=%s\nLength%d=-1\n", i+1, st.url, i+1, st.name, i+1)
+		}
+		fmt.Fprintf(w, "NumberOfEntries=%d\nVersion=2\n", len(list))
+	case "xspf":
+		pl := xspfPlaylist{Version: "1", XMLNS: "http://xspf.org/ns/0/", Title: "Nightride"}
+		for _, st := range list {
+			pl.Tracks = append(pl.Tracks, xspfTrack{Location: st.url, Title: st.name})
+		}
+		b, err := xml.MarshalIndent(pl, "", "  ")
+		if err != nil {
+			return err
+		}
+		io.WriteString(w, xml.Header)
+		w.Write(b)
+		fmt.Fprintln(w)
+	default:
+		return fmt.Errorf("unknown playlist format %q (want .m3u, .pls or .xspf)", kind)
+	}
+	return nil
+}
+
+func fetchPlaylist(u string) ([]playlistEntry, error) {
+	c := &http.Client{Timeout: 10 * time.Second}
+	resp, err := c.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: status %s", u, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	return parsePlaylist(playlistKind(u), b)
+}
+
+// resolveStreamURLs expands playlist URLs into the streams they list;
+// anything else is passed through untouched.
+func resolveStreamURLs(urls []string) []string {
+	var out []string
+	for _, u := range urls {
+		if playlistKind(u) == "" {
+			out = append(out, u)
+			continue
+		}
+		entries, err := fetchPlaylist(u)
+		if err != nil {
+			logf("playlist %s: %v", u, err)
+			continue
+		}
+		for _, e := range entries {
+			out = append(out, e.url)
+		}
+	}
+	return out
+}
+
+/* the "open URL" prompt in the player */
+
+func newURLInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "stream or playlist URL (.pls .m3u .xspf)"
+	ti.Prompt = fg(th.Accent).Bold(true).Render("URL> ")
+	ti.Width = 40
+	return ti
+}
+
+func (m *model) updatePrompt(k tea.KeyMsg) tea.Cmd {
+	switch k.String() {
+	case "esc":
+		m.prompting = false
+		m.urlInput.Blur()
+		return nil
+	case "enter":
+		raw := strings.TrimSpace(m.urlInput.Value())
+		m.prompting = false
+		m.urlInput.Blur()
+		if raw == "" {
+			return nil
+		}
+		m.promptStatus = "opening " + raw + "…"
+		return openURLCmd(raw)
+	}
+	var cmd tea.Cmd
+	m.urlInput, cmd = m.urlInput.Update(k)
+	return cmd
+}
+
+type urlStationMsg struct {
+	st  station
+	err error
+}
+
+func openURLCmd(raw string) tea.Cmd {
+	return func() tea.Msg {
+		if err := checkHTTPURL(raw); err != nil {
+			return urlStationMsg{err: fmt.Errorf("%s: %w", raw, err)}
+		}
+		st := station{name: raw, url: raw, meta: metaSource{kind: metaNone}}
+		if u, err := url.Parse(raw); err == nil {
+			st.name = u.Host + u.Path
+		}
+		if playlistKind(raw) != "" {
+			entries, err := fetchPlaylist(raw)
+			if err != nil {
+				return urlStationMsg{err: err}
+			}
+			st.url = entries[0].url
+			for _, e := range entries[1:] {
+				st.mirrors = append(st.mirrors, e.url)
+			}
+			if entries[0].title != "" {
+				st.name = entries[0].title
+			}
+		}
+		return urlStationMsg{st: st}
+	}
+}
+
+// playURLStation adds the opened stream to the list for this session
+// (import a playlist to keep it) and starts it.
+func (m *model) playURLStation(msg urlStationMsg) tea.Cmd {
+	if msg.err != nil {
+		logf("open url: %v", msg.err)
+		m.promptStatus = "open failed: " + msg.err.Error()
+		return nil
+	}
+	m.addStations([]station{msg.st})
+	idx := -1
+	for i, st := range stations {
+		if st.url == msg.st.url {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil
+	}
+	m.promptStatus = ""
+	m.stopCurrent()
+	m.playingIdx = idx
+	m.startTime = time.Now()
+	m.scrollOffset = 0
+	m.l.Select(idx)
+	return startStreamCmd(idx)
+}
+
+/* nightride import / export */
+
+func runImport(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: nightride import <playlist.m3u|.pls|.xspf|URL>")
+	}
+	src := args[0]
+	var (
+		entries []playlistEntry
+		err     error
+	)
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		entries, err = fetchPlaylist(src)
+	} else {
+		var b []byte
+		if b, err = os.ReadFile(src); err == nil {
+			entries, err = parsePlaylist(playlistKind(src), b)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Anything already in the list (a built-in, or a stations.json entry)
+	// is left alone: a new entry with its name would turn into an override,
+	// and this one's metadata source would replace the station's own.
+	known := map[string]bool{}
+	for _, st := range stations {
+		for _, u := range st.streamURLs() {
+			known[u] = true
+		}
+	}
+
+	// Playlists usually list a station's mirrors as entries with the same
+	// title; those become one station with several streams.
+	var add []catalogEntry
+	byName := map[string]int{}
+	for i, e := range entries {
+		name := e.title
+		if name == "" {
+			name = fmt.Sprintf("%s %d", strings.TrimSuffix(filepath.Base(src), filepath.Ext(src)), i+1)
+		}
+		if known[e.url] || findStation(catalogEntry{Name: name}) >= 0 {
+			fmt.Fprintf(os.Stderr, "skipping %q: already in the station list\n", name)
+			continue
+		}
+		if j, ok := byName[strings.ToLower(name)]; ok {
+			if err := checkHTTPURL(e.url); err != nil {
+				fmt.Fprintf(os.Stderr, "skipping mirror %q of %q: %v\n", e.url, name, err)
+				continue
+			}
+			add[j].Streams = append(add[j].Streams, e.url)
+			continue
+		}
+		ce := catalogEntry{Name: name, Streams: []string{e.url}, Meta: &catalogMeta{Type: metaNone}}
+		if err := ce.validate(false); err != nil {
+			fmt.Fprintf(os.Stderr, "skipping %q: %v\n", name, err)
+			continue
+		}
+		byName[strings.ToLower(name)] = len(add)
+		add = append(add, ce)
+	}
+	if len(add) == 0 {
+		return errors.New("nothing to import")
+	}
+	if err := saveCatalogEntries(add...); err != nil {
+		return err
+	}
+	fmt.Printf("imported %d stations into %s\n", len(add), catalogPath())
+	return nil
+}
+
+func runExport(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: nightride export <stations.m3u|.pls|.xspf>")
+	}
+	kind := playlistKind(args[0])
+	if kind == "" {
+		return fmt.Errorf("%s: want a .m3u, .pls or .xspf file name", args[0])
+	}
+	var b bytes.Buffer
+	if err := writePlaylist(&b, kind, stations); err != nil {
+		return err
+	}
+	if err := os.WriteFile(args[0], b.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("exported %d stations to %s\n", len(stations), args[0])
+	return nil
+}
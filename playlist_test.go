@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Exporting the list and importing the file again adds only the stations
+// that weren't there; built-ins keep their own metadata source.
+func TestExportImportRoundTrip(t *testing.T) {
+	t.Setenv("NIGHTRIDE_CONFIG_DIR", t.TempDir())
+	builtin := append([]station(nil), stations...)
+	t.Cleanup(func() { stations = builtin })
+
+	other := station{name: "Other Radio", url: "https://radio.example.com/live"}
+	for _, kind := range []string{"m3u", "pls", "xspf"} {
+		t.Run(kind, func(t *testing.T) {
+			stations = append([]station(nil), builtin...)
+			os.Remove(catalogPath())
+
+			var b bytes.Buffer
+			if err := writePlaylist(&b, kind, append(append([]station(nil), builtin...), other)); err != nil {
+				t.Fatal(err)
+			}
+			file := filepath.Join(t.TempDir(), "exported."+kind)
+			if err := os.WriteFile(file, b.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := runImport([]string{file}); err != nil {
+				t.Fatal(err)
+			}
+
+			var entries []catalogEntry
+			saved, err := os.ReadFile(catalogPath())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(saved, &entries); err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 || entries[0].Name != other.name {
+				t.Fatalf("imported %+v, want only %q", entries, other.name)
+			}
+
+			loadStationCatalog()
+			if len(stations) != len(builtin)+1 {
+				t.Fatalf("%d stations after import, want %d", len(stations), len(builtin)+1)
+			}
+			for i, st := range builtin {
+				if stations[i].meta != st.meta || stations[i].url != st.url {
+					t.Errorf("%s changed: %+v", st.name, stations[i])
+				}
+			}
+
+			// a second import of the same file finds nothing new
+			if err := runImport([]string{file}); err == nil {
+				t.Error("importing twice added stations again")
+			}
+		})
+	}
+}
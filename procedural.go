@@ -0,0 +1,294 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/* ───────────── generated visuals ───────────── */
+
+// Stations without a YouTube stream get an animated scene on the video
+// screen instead. Scenes draw into the same rgb24 buffer ffmpeg fills, so
+// they go through the same ASCII converters as real video.
+
+const (
+	sceneSun = iota
+	sceneStars
+	sceneRain
+	sceneCount
+)
+
+var sceneNames = [sceneCount]string{"sun", "starfield", "rain"}
+
+type ytSceneMsg struct{ session int }
+
+func sceneTick(session int) tea.Cmd {
+	return tea.Tick(33*time.Millisecond, func(time.Time) tea.Msg { return ytSceneMsg{session: session} })
+}
+
+// sceneAudio is what a scene reacts to, all 0..1.
+type sceneAudio struct {
+	level float64 // loudness, from the VU meters
+	bass  float64 // low end of the spectrum
+	pulse float64 // beat pulse, decaying after each onset
+}
+
+func (m model) sceneAudio() sceneAudio {
+	a := sceneAudio{level: (m.vu[0] + m.vu[1]) / 2, pulse: m.pulse}
+	_, h := m.vizSize()
+	if n := len(m.barHeights) / 6; n > 0 && h > 1 {
+		sum := 0
+		for _, v := range m.barHeights[:n] {
+			sum += v
+		}
+		a.bass = math.Min(1, float64(sum)/float64(n*(h-1)))
+	}
+	return a
+}
+
+type scene struct {
+	kind   int
+	t      float64
+	top    [3]float64 // station gradient, 0..255
+	bottom [3]float64
+	rng    *rand.Rand
+
+	stars [][3]float64 // x, y in -1..1, depth 0..1
+	drops [][4]float64 // x, y, speed, length; in pixels
+	flash float64
+}
+
+func newScene(kind int, iconKey string) *scene {
+	c := stationColors(iconKey)
+	s := &scene{kind: kind, rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	r, g, b := parseHexColor(c[0])
+	s.top = [3]float64{float64(r), float64(g), float64(b)}
+	r, g, b = parseHexColor(c[1])
+	s.bottom = [3]float64{float64(r), float64(g), float64(b)}
+	return s
+}
+
+// defaultScene gives each station its own look until the user picks one.
+func defaultScene(iconKey string) int {
+	switch iconKey {
+	case "horrorsynth":
+		return sceneRain
+	case "rekt":
+		return sceneStars
+	}
+	return sceneSun
+}
+
+// tint mixes the station's top and bottom colors at t and scales the
+// result by brightness v.
+func (s *scene) tint(t, v float64) (byte, byte, byte) {
+	t = math.Max(0, math.Min(1, t))
+	v = math.Max(0, math.Min(1, v))
+	c := func(i int) byte { return byte((s.top[i]*(1-t) + s.bottom[i]*t) * v) }
+	return c(0), c(1), c(2)
+}
+
+// render advances the scene one frame and draws it into rgb, w x h pixels.
+// aspect is pixel height over width, so circles stay round in any mode.
+func (s *scene) render(rgb []byte, w, h int, aspect float64, a sceneAudio) {
+	s.t += 1.0 / 30
+	clear(rgb)
+	switch s.kind {
+	case sceneStars:
+		s.renderStars(rgb, w, h, a)
+	case sceneRain:
+		s.renderRain(rgb, w, h, a)
+	default:
+		s.renderSun(rgb, w, h, aspect, a)
+	}
+}
+
+func setPixel(rgb []byte, w, x, y int, r, g, b byte) {
+	o := (y*w + x) * 3
+	rgb[o], rgb[o+1], rgb[o+2] = r, g, b
+}
+
+// renderSun is the striped sun setting over a perspective grid that
+// scrolls toward the viewer. The sun swells with the bass and the grid
+// speeds up and brightens with the level.
+func (s *scene) renderSun(rgb []byte, w, h int, aspect float64, a sceneAudio) {
+	horizon := h * 11 / 20
+	cx := float64(w) / 2
+	radius := float64(h) * 0.42 * (1 + 0.12*a.bass + 0.05*a.pulse)
+	sunBottom := float64(horizon)
+	sunTop := sunBottom - radius
+
+	for y := 0; y < horizon; y++ {
+		dy := float64(y) - sunBottom
+		for x := 0; x < w; x++ {
+			dx := (float64(x) - cx) / aspect
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+			// bands thicken toward the horizon and drift down
+			u := (float64(y) - sunTop) / radius
+			if u > 0.45 && math.Mod(u*14+s.t*0.8, 2) < (u-0.45)*2.2 {
+				continue
+			}
+			r, g, b := s.tint(u, 0.75+0.25*a.pulse)
+			setPixel(rgb, w, x, y, r, g, b)
+		}
+	}
+
+	// A row (or column) is on a grid line when the ground it covers spans a
+	// whole unit; far away every row does, and the fade hides that.
+	speed := 0.6 + 1.6*a.level
+	scroll := s.t * speed
+	bright := 0.45 + 0.4*a.level + 0.15*a.pulse
+	ground := float64(h - horizon)
+	cell := float64(w) / 10 // grid spacing at the bottom edge, in pixels
+	for y := horizon; y < h; y++ {
+		d := float64(y-horizon) + 0.5
+		near, far := ground/(d+0.5), ground/math.Max(0.25, d-0.5)
+		rowLine := math.Floor(near+scroll) != math.Floor(far+scroll)
+		fade := 0.3 + 0.7*math.Min(1, d/ground*1.6)
+		z := ground / d
+		for x := 0; x < w; x++ {
+			x0 := (float64(x) - cx) / cell * z
+			x1 := (float64(x+1) - cx) / cell * z
+			if !rowLine && math.Floor(x0) == math.Floor(x1) {
+				continue
+			}
+			r, g, b := s.tint(1-fade*0.5, bright*fade)
+			setPixel(rgb, w, x, y, r, g, b)
+		}
+	}
+}
+
+// renderStars flies through a field of stars; loudness sets the speed and
+// each beat brightens the whole field.
+func (s *scene) renderStars(rgb []byte, w, h int, a sceneAudio) {
+	n := max(60, w*h/40)
+	if len(s.stars) != n {
+		s.stars = make([][3]float64, n)
+		for i := range s.stars {
+			s.stars[i] = [3]float64{s.rng.Float64()*2 - 1, s.rng.Float64()*2 - 1, s.rng.Float64()}
+		}
+	}
+	speed := 0.004 + 0.03*a.level + 0.02*a.pulse
+	cx, cy := float64(w)/2, float64(h)/2
+	for i := range s.stars {
+		st := &s.stars[i]
+		st[2] -= speed
+		if st[2] <= 0.01 {
+			*st = [3]float64{s.rng.Float64()*2 - 1, s.rng.Float64()*2 - 1, 1}
+		}
+		x := int(cx + st[0]/st[2]*cx*0.5)
+		y := int(cy + st[1]/st[2]*cy*0.5)
+		if x < 0 || x >= w || y < 0 || y >= h {
+			*st = [3]float64{s.rng.Float64()*2 - 1, s.rng.Float64()*2 - 1, 1}
+			continue
+		}
+		v := (1 - st[2]) * (0.7 + 0.3*a.pulse)
+		r, g, b := s.tint(st[2], v)
+		setPixel(rgb, w, x, y, r, g, b)
+		// near stars streak back toward the centre
+		if st[2] < 0.3 {
+			tx := int(cx + st[0]/(st[2]+speed*3)*cx*0.5)
+			ty := int(cy + st[1]/(st[2]+speed*3)*cy*0.5)
+			if tx >= 0 && tx < w && ty >= 0 && ty < h {
+				r, g, b := s.tint(st[2], v*0.5)
+				setPixel(rgb, w, tx, ty, r, g, b)
+			}
+		}
+	}
+}
+
+// renderRain drops streaks down the screen, faster when it's loud, and
+// flashes the sky on strong beats.
+func (s *scene) renderRain(rgb []byte, w, h int, a sceneAudio) {
+	n := max(20, w/2)
+	if len(s.drops) != n {
+		s.drops = make([][4]float64, n)
+		for i := range s.drops {
+			s.drops[i] = s.newDrop(w, h, true)
+		}
+	}
+	if a.pulse > 0.95 && a.bass > 0.6 {
+		s.flash = 1
+	}
+	if s.flash > 0.02 {
+		for y := 0; y < h; y++ {
+			r, g, b := s.tint(0, s.flash*0.35*(1-float64(y)/float64(h)))
+			for x := 0; x < w; x++ {
+				setPixel(rgb, w, x, y, r, g, b)
+			}
+		}
+		s.flash *= 0.7
+	}
+	for i := range s.drops {
+		d := &s.drops[i]
+		d[1] += d[2] * (0.6 + 1.4*a.level)
+		if d[1]-d[3] > float64(h) {
+			*d = s.newDrop(w, h, false)
+		}
+		x := int(d[0])
+		for k := 0; k < int(d[3]); k++ {
+			y := int(d[1]) - k
+			if y < 0 || y >= h {
+				continue
+			}
+			fall := float64(k) / d[3]
+			r, g, b := s.tint(fall, (1-fall)*(0.6+0.4*a.level))
+			setPixel(rgb, w, x, y, r, g, b)
+		}
+	}
+}
+
+func (s *scene) newDrop(w, h int, anywhere bool) [4]float64 {
+	y := -s.rng.Float64() * float64(h) / 2
+	if anywhere {
+		y = s.rng.Float64() * float64(h)
+	}
+	speed := float64(h) / 40 * (0.6 + s.rng.Float64())
+	return [4]float64{float64(s.rng.Intn(max(1, w))), y, speed, 2 + speed*2}
+}
+
+func (y *ytModel) startScene(iconKey string) {
+	kind := y.sceneKind
+	if kind < 0 {
+		kind = defaultScene(iconKey)
+	}
+	y.scene = newScene(kind, iconKey)
+	y.loading = false
+	y.frame = ""
+}
+
+func (y *ytModel) nextScene() {
+	if y.scene == nil {
+		return
+	}
+	y.sceneKind = (y.scene.kind + 1) % sceneCount
+	y.scene.kind = y.sceneKind
+}
+
+func (y *ytModel) renderScene(a sceneAudio) {
+	w, h := y.pixelDims()
+	y.sceneBuf = resized(y.sceneBuf, w*h*3)
+	y.scene.render(y.sceneBuf, w, h, y.pixelAspect(), a)
+	y.frame = y.toASCII(y.sceneBuf, w, h)
+}
+
+func (y *ytModel) sceneView() string {
+	name := ""
+	if y.idx >= 0 && y.idx < len(stations) {
+		name = stations[y.idx].name
+	}
+	head := "Visuals · " + name + " · " + sceneNames[y.scene.kind]
+	if look := currentLook().String(); look != "" {
+		head += " · " + look
+	}
+	l := func(a string) string { return keys.label(scrYouTube, a) }
+	controls := "[" + l("prev-station") + "/" + l("next-station") + "] station · [" + l("scene") + "] scene · [" +
+		l("color") + "] " + ytModeNames[y.mode.next()] + " · [" + l("dither") + "] dither · [" +
+		l("stats") + "] stats · [" + l("back") + "] back"
+	return fg(th.Header).Render(head) + "\n" + y.frame + "\n" + fg(th.Dim).Render(padOrTrim(controls, max(10, y.cols)))
+}
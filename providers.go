@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/* ───────────── now-playing providers ───────────── */
+
+const (
+	metaNone      = "none"      // no titles, e.g. directory stations
+	metaNightride = "nightride" // Nightride /meta SSE, the default
+	metaIcecast   = "icecast"   // Icecast status-json.xsl polling
+	metaAzuraCast = "azuracast" // AzuraCast /api/nowplaying polling
+)
+
+const metaPollEvery = 15 * time.Second
+
+// metaSource says where a station's titles come from.
+type metaSource struct {
+	kind    string
+	url     string // SSE endpoint, status-json.xsl or AzuraCast base URL
+	station string // AzuraCast shortcode
+}
+
+// metaProvider pushes now-playing updates, keyed like metaAllMsg, until
+// ctx ends.
+type metaProvider interface {
+	name() string
+	run(ctx context.Context, out chan<- metaAllMsg)
+}
+
+// startMetaProviders starts one provider per endpoint used by list.
+func startMetaProviders(list []station) {
+	for _, p := range providersFor(list) {
+		logf("meta: starting %s", p.name())
+		go p.run(context.Background(), sseChan)
+	}
+}
+
+func providersFor(list []station) []metaProvider {
+	var (
+		out       []metaProvider
+		nightride = map[string]bool{}
+		ice       = map[string]*icecastProvider{}
+		azura     = map[string]*azuracastProvider{}
+	)
+	for _, st := range list {
+		key := st.id() + ".mp3"
+		switch st.meta.kind {
+		case metaNone:
+		case "", metaNightride:
+			u := st.meta.url
+			if u == "" {
+				u = defaultMetaURL
+			}
+			if !nightride[u] {
+				nightride[u] = true
+				out = append(out, nightrideProvider{url: u})
+			}
+		case metaIcecast:
+			p := ice[st.meta.url]
+			if p == nil {
+				p = &icecastProvider{url: st.meta.url, mounts: map[string]string{}}
+				ice[st.meta.url] = p
+				out = append(out, p)
+			}
+			p.mounts[mountPath(st.url)] = key
+		case metaAzuraCast:
+			p := azura[st.meta.url]
+			if p == nil {
+				p = &azuracastProvider{url: st.meta.url, shortcodes: map[string]string{}}
+				azura[st.meta.url] = p
+				out = append(out, p)
+			}
+			p.shortcodes[st.meta.station] = key
+		}
+	}
+	return out
+}
+
+func sendMeta(out chan<- metaAllMsg, update metaAllMsg) {
+	if len(update) == 0 {
+		return
+	}
+	select {
+	case out <- update:
+	default:
+	}
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func joinArtistTitle(artist, title string) string {
+	if artist == "" {
+		return title
+	}
+	if title == "" {
+		return artist
+	}
+	return artist + " - " + title
+}
+
+/* Nightride SSE */
+
+const defaultMetaURL = "https://nightride.fm/meta"
+
+type nightrideProvider struct{ url string }
+
+func (p nightrideProvider) name() string { return "nightride sse " + p.url }
+
+func (p nightrideProvider) run(ctx context.Context, out chan<- metaAllMsg) {
+	c := newSSEClient("meta", p.url, func(ev sseEvent) {
+		payload := strings.TrimSpace(ev.Data)
+		if payload == "" || payload == "keepalive" {
+			return
+		}
+
+		var entries []nowPlaying
+		if err := json.Unmarshal([]byte(payload), &entries); err != nil {
+			return
+		}
+
+		update := metaAllMsg{}
+		for _, np := range entries {
+			update[np.Station+".mp3"] = stationMeta{
+				title:     fmt.Sprintf("%s - %s", np.Artist, np.Title),
+				listeners: currentListeners(np.Station + ".mp3"),
+				cover:     np.coverURL(),
+			}
+		}
+		sendMeta(out, update)
+	})
+	c.Run(ctx)
+}
+
+/* Icecast */
+
+type icecastProvider struct {
+	url    string
+	mounts map[string]string // mount path -> station key
+}
+
+func (p *icecastProvider) name() string { return "icecast " + p.url }
+
+func (p *icecastProvider) run(ctx context.Context, out chan<- metaAllMsg) {
+	c := &http.Client{Timeout: 10 * time.Second}
+	for {
+		sources, err := fetchIcecastSources(c, p.url)
+		if err != nil {
+			logf("icecast %s: %v", p.url, err)
+		} else {
+			update := metaAllMsg{}
+			for _, s := range sources {
+				if key, ok := p.mounts[mountPath(s.ListenURL)]; ok {
+					update[key] = stationMeta{title: s.nowPlaying(), listeners: s.Listeners}
+				}
+			}
+			sendMeta(out, update)
+		}
+		if !sleepCtx(ctx, metaPollEvery) {
+			return
+		}
+	}
+}
+
+func mountPath(u string) string {
+	if p, err := url.Parse(u); err == nil {
+		return p.Path
+	}
+	return u
+}
+
+// fetchIcecastStations lists every mount on an Icecast server. Stream URLs
+// are rebuilt from the status page's host, since Icecast often reports
+// its internal hostname in listenurl.
+func fetchIcecastStations(c *http.Client, statusURL string) ([]station, error) {
+	base, err := url.Parse(statusURL)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := fetchIcecastSources(c, statusURL)
+	if err != nil {
+		return nil, err
+	}
+	var out []station
+	for _, s := range sources {
+		mount := mountPath(s.ListenURL)
+		if mount == "" || mount == "/" {
+			continue
+		}
+		stream := url.URL{Scheme: base.Scheme, Host: base.Host, Path: mount}
+		name := s.ServerName
+		if name == "" || name == "Unspecified name" {
+			name = strings.TrimPrefix(mount, "/")
+		}
+		out = append(out, station{
+			name:      name,
+			url:       stream.String(),
+			metaKey:   backendKey(base.Host, mount),
+			title:     s.nowPlaying(),
+			listeners: s.Listeners,
+			meta:      metaSource{kind: metaIcecast, url: statusURL},
+		})
+	}
+	return out, nil
+}
+
+/* AzuraCast */
+
+type azuracastProvider struct {
+	url        string
+	shortcodes map[string]string // station shortcode -> station key
+}
+
+func (p *azuracastProvider) name() string { return "azuracast " + p.url }
+
+type azuraNowPlaying struct {
+	Station struct {
+		Shortcode string `json:"shortcode"`
+	} `json:"station"`
+	Listeners struct {
+		Current int `json:"current"`
+	} `json:"listeners"`
+	NowPlaying struct {
+		Song struct {
+			Text   string `json:"text"`
+			Artist string `json:"artist"`
+			Title  string `json:"title"`
+			Art    string `json:"art"`
+		} `json:"song"`
+	} `json:"now_playing"`
+}
+
+type azuraStation struct {
+	Shortcode string `json:"shortcode"`
+	Name      string `json:"name"`
+	ListenURL string `json:"listen_url"`
+}
+
+func (p *azuracastProvider) run(ctx context.Context, out chan<- metaAllMsg) {
+	c := &http.Client{Timeout: 10 * time.Second}
+	for {
+		var all []azuraNowPlaying
+		if err := getJSON(c, strings.TrimRight(p.url, "/")+"/api/nowplaying", &all); err != nil {
+			logf("azuracast %s: %v", p.url, err)
+		} else {
+			update := metaAllMsg{}
+			for _, np := range all {
+				key, ok := p.shortcodes[np.Station.Shortcode]
+				if !ok {
+					continue
+				}
+				song := np.NowPlaying.Song
+				title := joinArtistTitle(song.Artist, song.Title)
+				if title == "" {
+					title = song.Text
+				}
+				update[key] = stationMeta{title: title, listeners: np.Listeners.Current, cover: song.Art}
+			}
+			sendMeta(out, update)
+		}
+		if !sleepCtx(ctx, metaPollEvery) {
+			return
+		}
+	}
+}
+
+func fetchAzuraCastStations(c *http.Client, base string) ([]station, error) {
+	base = strings.TrimRight(base, "/")
+	var list []azuraStation
+	if err := getJSON(c, base+"/api/stations", &list); err != nil {
+		return nil, err
+	}
+	host := base
+	if u, err := url.Parse(base); err == nil {
+		host = u.Host
+	}
+	var out []station
+	for _, s := range list {
+		if s.ListenURL == "" || s.Shortcode == "" {
+			continue
+		}
+		out = append(out, station{
+			name:    s.Name,
+			url:     s.ListenURL,
+			metaKey: backendKey(host, s.Shortcode),
+			meta:    metaSource{kind: metaAzuraCast, url: base, station: s.Shortcode},
+		})
+	}
+	return out, nil
+}
+
+func getJSON(c *http.Client, u string, v any) error {
+	resp, err := c.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %s", u, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+var keyJunkRe = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// backendKey builds a metaKey that stays unique across servers.
+func backendKey(host, name string) string {
+	k := strings.ToLower(host + "-" + strings.Trim(name, "/"))
+	return strings.Trim(keyJunkRe.ReplaceAllString(k, "-"), "-")
+}
+
+/* backends: whole station lists from a server */
+
+type backendConfig struct {
+	Type string `json:"type"` // "icecast" or "azuracast"
+	URL  string `json:"url"`
+}
+
+type stationsAddedMsg struct {
+	from     string
+	stations []station
+}
+
+// loadBackendsCmd fetches the station list of every configured backend.
+func loadBackendsCmd() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, b := range cfg.Backends {
+		b := b
+		cmds = append(cmds, func() tea.Msg {
+			c := &http.Client{Timeout: 10 * time.Second}
+			var (
+				list []station
+				err  error
+			)
+			switch b.Type {
+			case metaIcecast:
+				list, err = fetchIcecastStations(c, b.URL)
+			case metaAzuraCast:
+				list, err = fetchAzuraCastStations(c, b.URL)
+			default:
+				err = fmt.Errorf("unknown backend type %q", b.Type)
+			}
+			if err != nil {
+				logf("backend %s: %v", b.URL, err)
+				return nil
+			}
+			return stationsAddedMsg{from: b.URL, stations: list}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// addStations appends stations we don't have yet (by stream URL) to the
+// catalog and the list, and returns the ones actually added.
+func (m *model) addStations(list []station) []station {
+	var added []station
+	for _, st := range list {
+		dup := false
+		for _, have := range stations {
+			if have.url == st.url {
+				dup = true
+				break
+			}
+		}
+		if dup {
+			continue
+		}
+		i := len(stations)
+		stations = append(stations, st)
+		m.l.InsertItem(i, st)
+		m.originalTitles[i] = st.title
+		added = append(added, st)
+	}
+	if len(added) > 0 {
+		m.l.SetHeight(len(stations)*2 - 3)
+	}
+	return added
+}
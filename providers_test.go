@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// firstUpdate runs p against a stand-in server until its first update.
+func firstUpdate(t *testing.T, p metaProvider) metaAllMsg {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan metaAllMsg, 1)
+	go p.run(ctx, out)
+	select {
+	case m := <-out:
+		return m
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s sent nothing", p.name())
+		return nil
+	}
+}
+
+func serveJSON(t *testing.T, path, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestIcecastProvider(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		mounts map[string]string
+		want   metaAllMsg
+	}{
+		{
+			name: "single source",
+			body: `{"icestats":{"source":{"listenurl":"http://internal:8000/live","listeners":7,
+				"server_name":"Live","artist":"Perturbator","title":"Venger"}}}`,
+			mounts: map[string]string{"/live": "a-live"},
+			want:   metaAllMsg{"a-live": {title: "Perturbator - Venger", listeners: 7}},
+		},
+		{
+			name: "source array",
+			body: `{"icestats":{"source":[
+				{"listenurl":"http://internal:8000/one","listeners":1,"title":"Only Title"},
+				{"listenurl":"http://internal:8000/two","listeners":2,"artist":"Carpenter Brut","title":"Turbo Killer"},
+				{"listenurl":"http://internal:8000/other","listeners":3,"title":"Not Ours"}]}}`,
+			mounts: map[string]string{"/one": "s-one", "/two": "s-two"},
+			want: metaAllMsg{
+				"s-one": {title: "Only Title", listeners: 1},
+				"s-two": {title: "Carpenter Brut - Turbo Killer", listeners: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := serveJSON(t, "/status-json.xsl", tt.body)
+			p := &icecastProvider{url: srv.URL + "/status-json.xsl", mounts: tt.mounts}
+			if got := firstUpdate(t, p); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAzuraCastProvider(t *testing.T) {
+	body := `[
+		{"station":{"shortcode":"synth"},"listeners":{"current":12},
+		 "now_playing":{"song":{"text":"ignored","artist":"Kavinsky","title":"Nightcall","art":"https://radio.test/art.jpg"}}},
+		{"station":{"shortcode":"talk"},"listeners":{"current":3},
+		 "now_playing":{"song":{"text":"Live Show"}}},
+		{"station":{"shortcode":"unlisted"},"listeners":{"current":99},
+		 "now_playing":{"song":{"text":"Nope"}}}]`
+	srv := serveJSON(t, "/api/nowplaying", body)
+	p := &azuracastProvider{url: srv.URL + "/", shortcodes: map[string]string{"synth": "r-synth", "talk": "r-talk"}}
+	want := metaAllMsg{
+		"r-synth": {title: "Kavinsky - Nightcall", listeners: 12, cover: "https://radio.test/art.jpg"},
+		"r-talk":  {title: "Live Show", listeners: 3},
+	}
+	if got := firstUpdate(t, p); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/* ───────────── SSE client ───────────── */
+
+// sseEvent is one dispatched server-sent event.
+type sseEvent struct {
+	Event string // "message" unless the server named it
+	Data  string // data: lines joined with "\n"
+	ID    string // last event id seen on the stream
+}
+
+type sseState int
+
+const (
+	sseConnecting sseState = iota
+	sseOpen
+	sseRetrying
+	sseClosed
+)
+
+func (s sseState) String() string {
+	switch s {
+	case sseConnecting:
+		return "connecting"
+	case sseOpen:
+		return "open"
+	case sseRetrying:
+		return "retrying"
+	default:
+		return "closed"
+	}
+}
+
+var errSSENoContent = errors.New("server sent 204, not reconnecting")
+
+// sseClient follows the WHATWG event-stream format: multi-line data,
+// event/id/retry fields, comments, and Last-Event-ID on reconnect. Run
+// keeps reconnecting with exponential backoff until its context ends.
+type sseClient struct {
+	id      uint64 // keys the monitor's status; names repeat
+	name    string
+	url     string
+	http    *http.Client
+	onEvent func(sseEvent)
+	onState func(sseState, error)
+
+	lastID     string
+	retry      time.Duration // base delay, the server may change it
+	maxBackoff time.Duration
+}
+
+// sseMinRetry is the shortest reconnect delay, whatever the server asks
+// for; a "retry: 0" would otherwise spin on a failing endpoint.
+const sseMinRetry = 100 * time.Millisecond
+
+func newSSEClient(name, url string, onEvent func(sseEvent)) *sseClient {
+	return &sseClient{
+		id:         sseIDs.Add(1),
+		name:       name,
+		url:        url,
+		http:       &http.Client{Timeout: 0},
+		onEvent:    onEvent,
+		retry:      time.Second,
+		maxBackoff: 30 * time.Second,
+	}
+}
+
+func (c *sseClient) Run(ctx context.Context) {
+	defer sseForget(c.id)
+	delay := c.baseDelay()
+	for {
+		opened, err := c.connect(ctx)
+		if ctx.Err() != nil {
+			c.setState(sseClosed, nil)
+			return
+		}
+		if errors.Is(err, errSSENoContent) {
+			c.setState(sseClosed, err)
+			return
+		}
+		if opened {
+			delay = c.baseDelay()
+		}
+		c.setState(sseRetrying, err)
+		select {
+		case <-ctx.Done():
+			c.setState(sseClosed, nil)
+			return
+		case <-time.After(delay):
+		}
+		if !opened && delay < c.maxBackoff {
+			delay *= 2
+			if delay > c.maxBackoff {
+				delay = c.maxBackoff
+			}
+		}
+	}
+}
+
+func (c *sseClient) baseDelay() time.Duration {
+	if c.retry < sseMinRetry {
+		return sseMinRetry
+	}
+	return c.retry
+}
+
+// connect does one request and reads it to the end. The bool reports
+// whether the stream was actually opened.
+func (c *sseClient) connect(ctx context.Context) (bool, error) {
+	c.setState(sseConnecting, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if c.lastID != "" {
+		req.Header.Set("Last-Event-ID", c.lastID)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		return false, errSSENoContent
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("status %s", resp.Status)
+	}
+	c.setState(sseOpen, nil)
+	return true, c.read(ctx, resp.Body)
+}
+
+func (c *sseClient) read(ctx context.Context, r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	sc.Split(scanSSELines)
+
+	var (
+		data    strings.Builder
+		event   string
+		hasData bool
+		first   = true
+		id      = c.lastID // the id buffer; committed only at dispatch
+	)
+	for sc.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := sc.Text()
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+
+		if line == "" {
+			c.lastID = id
+			if hasData {
+				ev := sseEvent{Event: event, Data: strings.TrimSuffix(data.String(), "\n"), ID: c.lastID}
+				if ev.Event == "" {
+					ev.Event = "message"
+				}
+				sseCount(c.id)
+				if c.onEvent != nil {
+					c.onEvent(ev)
+				}
+			}
+			data.Reset()
+			event, hasData = "", false
+			continue
+		}
+		if line[0] == ':' {
+			continue // comment / keepalive
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				id = value
+			}
+		case "retry":
+			if !isDigits(value) {
+				break
+			}
+			if ms, err := strconv.Atoi(value); err == nil {
+				c.retry = time.Duration(ms) * time.Millisecond
+				if c.retry < sseMinRetry {
+					c.retry = sseMinRetry
+				}
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// scanSSELines splits on CRLF, LF or a lone CR.
+func scanSSELines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\r' {
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+				return i + 1, data[:i], nil
+			}
+			if !atEOF {
+				return 0, nil, nil // need one more byte to tell CR from CRLF
+			}
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+/* connection state, shown in the monitor */
+
+type sseStatus struct {
+	name   string
+	url    string
+	state  sseState
+	since  time.Time
+	err    string
+	events int
+}
+
+var (
+	sseMu       sync.Mutex
+	sseStatuses = map[uint64]*sseStatus{}
+	sseIDs      atomic.Uint64
+)
+
+func (c *sseClient) setState(s sseState, err error) {
+	sseMu.Lock()
+	st := sseStatuses[c.id]
+	if st == nil {
+		st = &sseStatus{name: c.name}
+		sseStatuses[c.id] = st
+	}
+	changed := st.state != s || st.since.IsZero()
+	st.url = c.url
+	if changed {
+		st.state, st.since = s, time.Now()
+	}
+	st.err = ""
+	if err != nil {
+		st.err = err.Error()
+	}
+	sseMu.Unlock()
+
+	if changed || err != nil {
+		if err != nil {
+			logf("sse %s: %s (%v)", c.name, s, err)
+		} else {
+			logf("sse %s: %s", c.name, s)
+		}
+	}
+	if c.onState != nil {
+		c.onState(s, err)
+	}
+}
+
+func sseCount(id uint64) {
+	sseMu.Lock()
+	if st := sseStatuses[id]; st != nil {
+		st.events++
+	}
+	sseMu.Unlock()
+}
+
+// sseForget drops a stopped client from the monitor.
+func sseForget(id uint64) {
+	sseMu.Lock()
+	delete(sseStatuses, id)
+	sseMu.Unlock()
+}
+
+func sseStatusLines() []string {
+	sseMu.Lock()
+	defer sseMu.Unlock()
+	ids := make([]uint64, 0, len(sseStatuses))
+	for id := range sseStatuses {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := sseStatuses[ids[i]], sseStatuses[ids[j]]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		return ids[i] < ids[j]
+	})
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		st := sseStatuses[id]
+		line := fmt.Sprintf("%-18s %-10s %5d events  since %s", st.name, st.state, st.events, st.since.Format("15:04:05"))
+		if st.err != "" {
+			line += "  " + st.err
+		}
+		out = append(out, line)
+	}
+	return out
+}
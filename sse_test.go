@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSERetryFloor(t *testing.T) {
+	tests := []struct {
+		field string
+		want  time.Duration
+	}{
+		{"retry: 0", sseMinRetry},
+		{"retry: 5", sseMinRetry},
+		{"retry: 2500", 2500 * time.Millisecond},
+		{"retry: soon", time.Second}, // not a number: ignored
+	}
+	for _, tt := range tests {
+		c := newSSEClient("test", "http://127.0.0.1/", func(sseEvent) {})
+		c.read(context.Background(), strings.NewReader(tt.field+"\n\n"))
+		if c.retry != tt.want || c.baseDelay() != tt.want {
+			t.Errorf("%q: retry %v, want %v", tt.field, c.retry, tt.want)
+		}
+	}
+}
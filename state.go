@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/* ───────────── session state ───────────── */
+
+// sessionState is what we remember between launches. The station is kept
+// by name so edits to stations.json don't shift it onto another one.
+type sessionState struct {
+	Station    string  `json:"station"`
+	Playing    bool    `json:"playing"`
+	Horizontal bool    `json:"horizontalLayout"`
+	YTMode     string  `json:"ytMode"`
+	YTColor    bool    `json:"ytColor,omitempty"` // before ytMode
+	Dither     string  `json:"dither,omitempty"`
+	Contrast   float64 `json:"contrast,omitempty"`
+	Gamma      float64 `json:"gamma,omitempty"`
+	Volume     *int    `json:"volume"` // nil in files from before volume was saved
+	Tab        int     `json:"tab"`
+}
+
+func statePath() string { return filepath.Join(configDir(), "state.json") }
+
+func loadState() (sessionState, bool) {
+	b, err := os.ReadFile(statePath())
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logf("state: %v", err)
+		}
+		return sessionState{}, false
+	}
+	var st sessionState
+	if err := json.Unmarshal(b, &st); err != nil {
+		logf("state %s: %v", statePath(), err)
+		return sessionState{}, false
+	}
+	return st, true
+}
+
+func saveState(st sessionState) error {
+	b, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(configDir(), 0o755); err != nil {
+		return err
+	}
+	tmp := statePath() + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, statePath())
+}
+
+func (r rootModel) sessionState() sessionState {
+	vol := volumePct
+	st := sessionState{
+		Playing:    r.player.playingIdx >= 0,
+		Horizontal: r.player.isHorizontalLayout,
+		YTMode:     ytModeNames[r.yt.mode],
+		Dither:     ditherNames[currentLook().dither],
+		Contrast:   currentLook().contrast,
+		Gamma:      currentLook().gamma,
+		Volume:     &vol,
+		Tab:        r.active,
+	}
+	idx := r.player.playingIdx
+	if idx < 0 {
+		idx = r.player.l.Index()
+	}
+	if idx >= 0 && idx < len(stations) {
+		st.Station = stations[idx].name
+	}
+	return st
+}
+
+func (r *rootModel) restore(st sessionState) {
+	idx := 0
+	for i, s := range stations {
+		if strings.EqualFold(s.name, st.Station) {
+			idx = i
+			break
+		}
+	}
+	m := &r.player
+	m.l.Select(idx)
+	if idx < len(stations) {
+		m.updateSelectorColors(stations[idx].iconKey())
+	}
+	m.playingIdx = -1
+	if st.Playing {
+		m.playingIdx = idx
+	}
+	m.isHorizontalLayout = st.Horizontal
+	if mode, ok := parseYTMode(st.YTMode); ok {
+		r.yt.mode = mode
+	} else if st.YTColor {
+		r.yt.mode = ytColor
+	}
+	contrast, gamma := st.Contrast, st.Gamma
+	if contrast == 0 {
+		contrast = 1
+	}
+	if gamma == 0 {
+		gamma = 1
+	}
+	setYTLook(parseDither(st.Dither), contrast, gamma)
+	volumePct = 100
+	if st.Volume != nil {
+		volumePct = min(150, max(0, *st.Volume))
+	}
+	if st.Tab == 0 || st.Tab == 1 {
+		r.active = st.Tab
+	}
+}
+
+// resumeCmd starts whatever was playing when the app last quit (the first
+// station on a fresh start).
+func (m model) resumeCmd() tea.Cmd {
+	if m.playingIdx < 0 || m.playingIdx >= len(stations) {
+		return nil
+	}
+	return startStreamCmd(m.playingIdx)
+}
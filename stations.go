@@ -1,15 +1,25 @@
 package main
 
 import (
+	"fmt"
+	"net/url"
 	"strings"
 )
 
 /* ─────────────  Station Data  ───────────── */
 
+// nightrideStreamHost serves the built-in stations.
+const nightrideStreamHost = "stream.nightride.fm"
+
 type station struct {
 	name, url string
+	mirrors   []string // extra stream URLs tried when url fails
+	metaKey   string   // key in the now-playing feed, defaults to the stream file name
+	meta      metaSource
 	title     string
 	listeners int
+	trend     []int  // listener samples for the last hour
+	cover     string // artwork URL of the current track, if the source has one
 	youtube   string // empty if no video source
 }
 
@@ -25,14 +35,55 @@ var stations = []station{
 	{name: "Rektory",     url: "https://stream.nightride.fm/rektory.mp3",     youtube: ""}, // none provided
 }
 
-func (s station) Title() string       { return s.name }
-func (s station) Description() string { return s.title }
+func (s station) Title() string {
+	if sp := sparkline(s.trend); sp != "" {
+		return s.name + "  " + sp
+	}
+	return s.name
+}
+
+func (s station) Description() string {
+	if s.listeners > 0 {
+		return fmt.Sprintf("[%d] %s", s.listeners, s.title)
+	}
+	return s.title
+}
+
 func (s station) FilterValue() string { return s.name }
+// id is the station's key for metadata, art, colors and the Discord
+// image. Built-in streams are named by file (darksynth.mp3); anything
+// else gets host and path, since "/stream" or "/live" say nothing.
 func (s station) id() string {
+	if s.metaKey != "" {
+		return s.metaKey
+	}
+	if u, err := url.Parse(s.url); err == nil && u.Host != nightrideStreamHost {
+		return backendKey(u.Host, u.Path)
+	}
 	key := strings.ToLower(stationKey(s.url))
 	return strings.TrimSuffix(key, ".mp3")
 }
 
+// iconKey picks the art, colors and Discord image for the station.
+func (s station) iconKey() string {
+	if k := s.id(); k != "nightride" {
+		return k
+	}
+	return "nrfm"
+}
+
+// pageURL is where the Discord "Listen" button points.
+func (s station) pageURL() string {
+	if s.meta.kind == "" || s.meta.kind == metaNightride {
+		return "https://nightride.fm/?station=" + s.id()
+	}
+	return s.url
+}
+
+func (s station) streamURLs() []string {
+	return append([]string{s.url}, s.mirrors...)
+}
+
 func stationKey(u string) string {
 	return u[strings.LastIndex(u, "/")+1:]
 }
\ No newline at end of file
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+/* ───────────── themes ───────────── */
+
+// theme holds every color the UI draws with. Theme files are JSON with the
+// same keys; anything left out keeps the default theme's value.
+type theme struct {
+	Name     string         `json:"name"`
+	Mono     bool           `json:"mono,omitempty"` // no colors at all, selection shown reversed
+	Header   lipgloss.Color `json:"header"`         // station name, screen titles
+	Accent   lipgloss.Color `json:"accent"`         // help box, prompts, alerts
+	Dim      lipgloss.Color `json:"dim"`            // status and hint lines
+	Muted    lipgloss.Color `json:"muted"`          // list title, placeholder messages
+	Border   lipgloss.Color `json:"border"`         // monitor box
+	IRC      lipgloss.Color `json:"irc"`
+	IRCDark  lipgloss.Color `json:"ircDark"`
+	IRCDim   lipgloss.Color `json:"ircDim"`
+	SelectFg lipgloss.Color `json:"selectFg"` // text on the IRC selection bar
+
+	// Gradient is used for stations without colors of their own. Stations
+	// overrides per icon key; the key "*" replaces every station's colors.
+	Gradient []string            `json:"gradient"`
+	Stations map[string][]string `json:"stations,omitempty"`
+}
+
+var defaultTheme = theme{
+	Name:     "default",
+	Header:   "#FFD75F",
+	Accent:   "#ff386f",
+	Dim:      "#888",
+	Muted:    "#aaa",
+	IRC:      "#DB2777",
+	IRCDark:  "#ac215f",
+	IRCDim:   "#6B7280",
+	SelectFg: "#000",
+	Gradient: []string{"#ff386f", "#7d3cff"},
+}
+
+var bundledThemes = []theme{
+	defaultTheme,
+	{
+		Name:     "high-contrast",
+		Header:   "#FFFF00",
+		Accent:   "#FF5FFF",
+		Dim:      "#FFFFFF",
+		Muted:    "#FFFFFF",
+		Border:   "#FFFFFF",
+		IRC:      "#FF5FFF",
+		IRCDark:  "#FF00AF",
+		IRCDim:   "#D0D0D0",
+		SelectFg: "#000000",
+		Gradient: []string{"#FFFFFF", "#00FFFF"},
+		Stations: map[string][]string{"*": {"#FFFFFF", "#00FFFF"}},
+	},
+	{
+		Name:     "light",
+		Header:   "#8A4B00",
+		Accent:   "#C2185B",
+		Dim:      "#555555",
+		Muted:    "#444444",
+		Border:   "#999999",
+		IRC:      "#AD1457",
+		IRCDark:  "#880E4F",
+		IRCDim:   "#4B5563",
+		SelectFg: "#FFFFFF",
+		Gradient: []string{"#C2185B", "#4527A0"},
+		Stations: map[string][]string{
+			"nrfm":        {"#C2185B", "#4527A0"},
+			"chillsynth":  {"#0D47A1", "#6A1B9A"},
+			"datawave":    {"#00695C", "#1565C0"},
+			"spacesynth":  {"#4527A0", "#283593"},
+			"darksynth":   {"#B71C1C", "#4A148C"},
+			"horrorsynth": {"#8E0000", "#311B92"},
+			"ebsm":        {"#2E7D32", "#1B5E20"},
+			"rektory":     {"#E65100", "#BF360C"},
+			"rekt":        {"#AD1457", "#6A1B9A"},
+		},
+	},
+	{
+		Name: "mono",
+		Mono: true,
+	},
+}
+
+var (
+	textColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}){1,2}$`)
+	th          = defaultTheme
+	themes      []theme
+	themeIdx    int
+	baseProfile = lipgloss.ColorProfile()
+	noColor     = os.Getenv("NO_COLOR") != ""
+)
+
+func themesDir() string { return filepath.Join(configDir(), "themes") }
+
+// loadThemes collects the bundled themes and any *.json in the themes
+// directory (a file named like a bundled theme replaces it), then applies
+// cfg.Theme. NO_COLOR always starts in mono.
+func loadThemes() {
+	themes = append(themes[:0], bundledThemes...)
+	files, _ := filepath.Glob(filepath.Join(themesDir(), "*.json"))
+	sort.Strings(files)
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			logf("theme %s: %v", f, err)
+			continue
+		}
+		t := defaultTheme
+		t.Stations = nil
+		if err := json.Unmarshal(b, &t); err != nil {
+			logf("theme %s: %v", f, err)
+			continue
+		}
+		if t.Name == "" {
+			t.Name = strings.TrimSuffix(filepath.Base(f), ".json")
+		}
+		t.check(f)
+		if i := themeIndex(t.Name); i >= 0 {
+			themes[i] = t
+		} else {
+			themes = append(themes, t)
+		}
+	}
+
+	name := cfg.Theme
+	if noColor {
+		name = "mono"
+	}
+	if name == "" {
+		name = "default"
+	}
+	i := themeIndex(name)
+	if i < 0 {
+		logf("theme %q not found, using default", name)
+		i = 0
+	}
+	setTheme(i)
+}
+
+// check logs and drops the colors in a theme file that wouldn't render,
+// falling back to the default theme's. Text colors may be anything lipgloss
+// takes (#rgb, #rrggbb or an ANSI number); gradients are blended channel
+// by channel and need #rrggbb.
+func (t *theme) check(file string) {
+	fields := []struct {
+		key string
+		c   *lipgloss.Color
+		def lipgloss.Color
+	}{
+		{"header", &t.Header, defaultTheme.Header},
+		{"accent", &t.Accent, defaultTheme.Accent},
+		{"dim", &t.Dim, defaultTheme.Dim},
+		{"muted", &t.Muted, defaultTheme.Muted},
+		{"border", &t.Border, defaultTheme.Border},
+		{"irc", &t.IRC, defaultTheme.IRC},
+		{"ircDark", &t.IRCDark, defaultTheme.IRCDark},
+		{"ircDim", &t.IRCDim, defaultTheme.IRCDim},
+		{"selectFg", &t.SelectFg, defaultTheme.SelectFg},
+	}
+	for _, f := range fields {
+		if !validTextColor(string(*f.c)) {
+			logf("theme %s: %s: bad color %q, using %q", file, f.key, *f.c, f.def)
+			*f.c = f.def
+		}
+	}
+	if !validGradient(t.Gradient) {
+		logf("theme %s: gradient: want two #rrggbb colors, got %q", file, t.Gradient)
+		t.Gradient = defaultTheme.Gradient
+	}
+	for k, c := range t.Stations {
+		if !validGradient(c) {
+			logf("theme %s: stations.%s: want two #rrggbb colors, got %q", file, k, c)
+			delete(t.Stations, k)
+		}
+	}
+}
+
+func validTextColor(c string) bool {
+	if c == "" || textColorRe.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+func validGradient(c []string) bool {
+	if len(c) != 2 {
+		return false
+	}
+	for _, s := range c {
+		if !hexColorRe.MatchString(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func themeIndex(name string) int {
+	for i, t := range themes {
+		if strings.EqualFold(t.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func setTheme(i int) {
+	themeIdx = i
+	th = themes[i]
+	if th.Mono || noColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	} else {
+		lipgloss.SetColorProfile(baseProfile)
+	}
+	applyZuseTheme()
+}
+
+func nextTheme() string {
+	setTheme((themeIdx + 1) % len(themes))
+	logf("theme: %s", th.Name)
+	return th.Name
+}
+
+// stationColors is the gradient pair for a station under the active theme.
+func stationColors(iconKey string) []string {
+	if c, ok := th.Stations[iconKey]; ok && len(c) == 2 {
+		return c
+	}
+	if c, ok := th.Stations["*"]; ok && len(c) == 2 {
+		return c
+	}
+	if c, ok := StationColors[iconKey]; ok {
+		return c
+	}
+	if len(th.Gradient) == 2 {
+		return th.Gradient
+	}
+	return defaultTheme.Gradient
+}
+
+func fg(c lipgloss.Color) lipgloss.Style { return lipgloss.NewStyle().Foreground(c) }
+
+// applyZuseTheme rebuilds the IRC package-level styles. Lines already in
+// the chat history keep the colors they were rendered with.
+func applyZuseTheme() {
+	pink, darkPink = th.IRC, th.IRCDark
+	stylePink = fg(pink)
+	stylePinkB = stylePink.Bold(true)
+	styleDim = fg(th.IRCDim)
+	styleDarkPink = fg(darkPink)
+	if th.Mono {
+		styleSel = lipgloss.NewStyle().Reverse(true)
+		styleDarkSel = lipgloss.NewStyle().Reverse(true).Faint(true)
+	} else {
+		styleSel = fg(th.SelectFg).Background(pink)
+		styleDarkSel = fg(th.SelectFg).Background(darkPink)
+	}
+	titleStyle = titleStyle.Background(darkPink).Foreground(th.SelectFg)
+	if th.Mono {
+		titleStyle = titleStyle.Reverse(true)
+	} else {
+		titleStyle = titleStyle.Reverse(false)
+	}
+	box = box.BorderForeground(pink)
+}
+
+// applyTheme pushes the active theme into the state that renders styles
+// once and keeps the result: delegates and input prompts.
+func (r *rootModel) applyTheme() {
+	r.player.urlInput.Prompt = fg(th.Accent).Bold(true).Render("URL> ")
+	r.player.l.Styles.Title = r.player.l.Styles.Title.Foreground(th.Muted)
+	if i := r.player.l.Index(); i >= 0 && i < len(stations) {
+		r.player.updateSelectorColors(stations[i].iconKey())
+	}
+	r.disc.input.Prompt = fg(th.Accent).Bold(true).Render("> ")
+	r.disc.list.SetDelegate(stationDelegate("nrfm"))
+	r.irc.m.applyTheme()
+}
+
+func (m *ircModel) applyTheme() {
+	m.darkDel, m.brightDel = ircDelegates()
+	m.applyListFocus()
+	for i := range m.formInputs {
+		m.formInputs[i].Prompt = stylePinkB.Render(" > ")
+		m.formInputs[i].TextStyle = stylePink
+	}
+	m.chatInput.Prompt = stylePinkB.Render("> ")
+	m.chatInput.TextStyle = stylePink
+}
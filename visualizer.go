@@ -0,0 +1,243 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+/* ───────────── visualizer modes ───────────── */
+
+// vizFrame is what the analyzer publishes each tick: spectrum columns, a
+// decimated stereo trace for the scope, and per-channel RMS for the meters.
+type vizFrame struct {
+	bands []float64
+	scope [][2]float64
+	rms   [2]float64
+	bpm   float64
+	beats uint64 // running onset count
+}
+
+const (
+	vizLogo = iota
+	vizBars
+	vizScope
+	vizWaterfall
+	vizVU
+	vizModes
+)
+
+var vizNames = [vizModes]string{"logo", "spectrum", "oscilloscope", "waterfall", "VU meters"}
+
+const (
+	vuFloorDB   = -48.0
+	vuPeakHold  = 1500 * time.Millisecond
+	vuPeakFall  = 0.02 // per tick once the hold runs out
+	vuRelease   = 0.12
+	vuBarIndent = 4
+)
+
+// feedViz keeps the per-mode state that outlives a single frame. It runs
+// after the bar heights for this frame have been smoothed.
+func (m *model) feedViz(f vizFrame, height int) {
+	m.scope = f.scope
+
+	for ch := 0; ch < 2; ch++ {
+		lvl := 0.0
+		if f.rms[ch] > 0 {
+			db := 20 * math.Log10(f.rms[ch])
+			lvl = math.Min(1, math.Max(0, (db-vuFloorDB)/-vuFloorDB))
+		}
+		if lvl > m.vu[ch] {
+			m.vu[ch] = lvl
+		} else {
+			m.vu[ch] -= (m.vu[ch] - lvl) * vuRelease
+		}
+		switch {
+		case m.vu[ch] >= m.vuPeak[ch]:
+			m.vuPeak[ch], m.vuPeakAt[ch] = m.vu[ch], time.Now()
+		case time.Since(m.vuPeakAt[ch]) > vuPeakHold:
+			m.vuPeak[ch] = math.Max(m.vu[ch], m.vuPeak[ch]-vuPeakFall)
+		}
+	}
+
+	if m.vizMode == vizWaterfall {
+		// recycle the row that scrolls off the bottom
+		var row []float64
+		if len(m.waterfall) >= height {
+			row = m.waterfall[height-1]
+			m.waterfall = m.waterfall[:height]
+		} else {
+			m.waterfall = append(m.waterfall, nil)
+		}
+		copy(m.waterfall[1:], m.waterfall)
+		if cap(row) < len(m.barHeights) {
+			row = make([]float64, len(m.barHeights))
+		}
+		row = row[:len(m.barHeights)]
+		for i, h := range m.barHeights {
+			row[i] = math.Min(1, float64(h)/float64(height-1))
+		}
+		m.waterfall[0] = row
+	}
+}
+
+func (m model) renderViz(iconKey string) string {
+	w, h := m.vizSize()
+	grad := pulsed(createGradient(iconKey, h), m.pulse)
+	switch m.vizMode {
+	case vizBars:
+		return renderBars(m.barHeights, w, h, grad)
+	case vizScope:
+		return renderScope(m.scope, w, h, grad)
+	case vizWaterfall:
+		return renderWaterfall(m.waterfall, w, h, grad)
+	case vizVU:
+		return renderVU(m.vu, m.vuPeak, w, h, grad)
+	}
+	return renderLogo(m.barHeights, stationLogo(iconKey, w, h), grad)
+}
+
+func paint(grad func(int) string, y int, s string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(grad(y))).Render(s)
+}
+
+// renderBars fills the whole panel with the spectrum.
+func renderBars(heights []int, w, h int, grad func(int) string) string {
+	lines := make([]string, h)
+	for y := 0; y < h; y++ {
+		row := make([]rune, w)
+		for x := range row {
+			row[x] = ' '
+			if x < len(heights) && heights[x] >= h-y {
+				row[x] = '█'
+			}
+		}
+		lines[y] = paint(grad, y, string(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderScope draws left and right as two braille traces, left on top.
+func renderScope(scope [][2]float64, w, h int, grad func(int) string) string {
+	dw, dh := w*2, h*4
+	dots := make([]bool, dw*dh)
+	set := func(x, y int) {
+		if x >= 0 && x < dw && y >= 0 && y < dh {
+			dots[y*dw+x] = true
+		}
+	}
+	half := dh / 2
+	prev := [2]int{-1, -1}
+	for x := 0; x < dw && len(scope) > 0; x++ {
+		s := scope[x*len(scope)/dw]
+		for ch := 0; ch < 2; ch++ {
+			mid := half/2 + ch*half
+			y := mid - int(math.Max(-1, math.Min(1, s[ch]))*float64(half/2-1))
+			set(x, y)
+			// join to the previous column so fast swings stay a line
+			if p := prev[ch]; p >= 0 {
+				for yy := min(p, y) + 1; yy < max(p, y); yy++ {
+					set(x, yy)
+				}
+			}
+			prev[ch] = y
+		}
+	}
+	for x := 0; x < dw; x++ {
+		if !dots[(half-1)*dw+x] {
+			dots[(half-1)*dw+x] = x%4 == 0 // faint divider between channels
+		}
+	}
+	return brailleLines(dots, dw, dh, grad)
+}
+
+// braille cell bit for the dot at (dx, dy) within its 2x4 cell
+var brailleBits = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+func brailleLines(dots []bool, dw, dh int, grad func(int) string) string {
+	w, h := dw/2, dh/4
+	lines := make([]string, h)
+	for cy := 0; cy < h; cy++ {
+		row := make([]rune, w)
+		for cx := 0; cx < w; cx++ {
+			r := rune(0x2800)
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					if dots[(cy*4+dy)*dw+cx*2+dx] {
+						r |= brailleBits[dy][dx]
+					}
+				}
+			}
+			row[cx] = r
+		}
+		lines[cy] = paint(grad, cy, string(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderWaterfall scrolls the spectrum down the panel, newest on top. The
+// gradient is indexed by intensity so hot cells take the top color.
+func renderWaterfall(rows [][]float64, w, h int, grad func(int) string) string {
+	ramp := []rune(" .:-=+*#%@")
+	lines := make([]string, h)
+	for y := 0; y < h; y++ {
+		var b strings.Builder
+		for x := 0; x < w; x++ {
+			v := 0.0
+			if y < len(rows) && x < len(rows[y]) {
+				v = rows[y][x]
+			}
+			if v <= 0 {
+				b.WriteByte(' ')
+				continue
+			}
+			ch := ramp[min(len(ramp)-1, int(v*float64(len(ramp))))]
+			b.WriteString(paint(grad, int((1-v)*float64(h-1)), string(ch)))
+		}
+		lines[y] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderVU draws two horizontal meters with a held peak marker, centred
+// in the panel. Color runs along the bar.
+func renderVU(vu, peak [2]float64, w, h int, grad func(int) string) string {
+	barW := max(4, w-vuBarIndent-1)
+	meter := func(label string, v, p float64) string {
+		var b strings.Builder
+		b.WriteString(label)
+		fill := int(v * float64(barW))
+		pk := min(barW-1, int(p*float64(barW)))
+		for x := 0; x < barW; x++ {
+			y := x * (h - 1) / max(1, barW-1)
+			switch {
+			case x < fill:
+				b.WriteString(paint(grad, y, "█"))
+			case x == pk && p > 0:
+				b.WriteString(paint(grad, y, "▌"))
+			default:
+				b.WriteString(paint(grad, y, "·"))
+			}
+		}
+		return b.String()
+	}
+	scale := strings.Repeat(" ", vuBarIndent)
+	for _, db := range []int{-48, -36, -24, -12, 0} {
+		pos := int(float64(db-int(vuFloorDB)) / -vuFloorDB * float64(barW-1))
+		for lipgloss.Width(scale) < vuBarIndent+pos-1 {
+			scale += " "
+		}
+		scale += strconv.Itoa(db)
+	}
+
+	out := make([]string, h)
+	top := max(0, (h-5)/2)
+	out[top] = meter(" L  ", vu[0], peak[0])
+	out[min(h-1, top+2)] = meter(" R  ", vu[1], peak[1])
+	out[min(h-1, top+4)] = fg(th.Dim).Render(padOrTrim(scale, w))
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/* ───────────── watchlist ───────────── */
+
+// keywordList matches "Artist - Title" strings against user terms,
+// case-insensitively.
+type keywordList []string
+
+func newKeywordList(terms []string) keywordList {
+	var kl keywordList
+	for _, t := range terms {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" {
+			kl = append(kl, t)
+		}
+	}
+	return kl
+}
+
+// match returns the first term found in title.
+func (kl keywordList) match(title string) (string, bool) {
+	if title == "" {
+		return "", false
+	}
+	lt := strings.ToLower(title)
+	for _, t := range kl {
+		if strings.Contains(lt, t) {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+const watchAlertTTL = 45 * time.Second
+
+type watchAlert struct {
+	idx     int
+	station string
+	title   string
+	term    string
+	at      time.Time
+}
+
+// checkWatchlist is called for every title change on any station.
+func (m *model) checkWatchlist(idx int, title string) {
+	term, ok := m.watch.match(title)
+	if !ok {
+		return
+	}
+	name := stations[idx].name
+	logf("watchlist: %q matched %q on %s", term, title, name)
+	if idx == m.playingIdx {
+		return
+	}
+	m.alert = &watchAlert{idx: idx, station: name, title: title, term: term, at: time.Now()}
+}
+
+/* ───────────── blocklist ───────────── */
+
+// blockHop remembers where we came from so we can go back once the
+// blocked track is over.
+type blockHop struct {
+	from  int
+	to    int
+	title string
+}
+
+// checkBlocklist runs after every metadata update. If the playing
+// station's title is blocked it hops to the next station whose current
+// title is clean; with blocklistReturn set it goes back when the
+// blocked track ends.
+func (r *rootModel) checkBlocklist() tea.Cmd {
+	p := r.player.playingIdx
+	if h := r.hop; h != nil {
+		if p != h.to || h.from >= len(stations) {
+			r.hop = nil // user moved on
+		} else if t := stations[h.from].title; t != h.title {
+			if _, blocked := r.block.match(t); blocked {
+				h.title = t
+			} else {
+				r.hop = nil
+				if cfg.BlocklistReturn {
+					logf("blocklist: %q ended on %s, returning", h.title, stations[h.from].name)
+					return r.switchAudioTo(h.from)
+				}
+			}
+		}
+	}
+
+	if p < 0 || p >= len(stations) {
+		return nil
+	}
+	title := stations[p].title
+	term, blocked := r.block.match(title)
+	if !blocked {
+		return nil
+	}
+	next := -1
+	for d := 1; d < len(stations); d++ {
+		j := (p + d) % len(stations)
+		if _, b := r.block.match(stations[j].title); !b {
+			next = j
+			break
+		}
+	}
+	if next < 0 {
+		logf("blocklist: %q matched %q on %s, no clean station to hop to", term, title, stations[p].name)
+		return nil
+	}
+	logf("blocklist: %q matched %q on %s, hopping to %s", term, title, stations[p].name, stations[next].name)
+	if r.hop == nil {
+		r.hop = &blockHop{from: p, title: title}
+	}
+	r.hop.to = next
+	return r.switchAudioTo(next)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/muesli/termenv"
+)
+
+/* ───────────── video render modes ───────────── */
+
+// The video screen can draw each frame several ways. Half-block and
+// braille pack more than one pixel into a cell, so ffmpeg is asked for a
+// bigger frame in those modes.
+type ytMode int
+
+const (
+	ytMono ytMode = iota
+	ytColor
+	ytHalf
+	ytBraille
+	ytPixels // kitty or sixel graphics
+	ytModeCount
+)
+
+var ytModeNames = [ytModeCount]string{"mono", "color", "half-block", "braille", "pixels"}
+
+// next is the mode C switches to; pixels only when the terminal can.
+func (m ytMode) next() ytMode {
+	m = (m + 1) % ytModeCount
+	if m == ytPixels && !pixelsOK() {
+		m = (m + 1) % ytModeCount
+	}
+	return m
+}
+
+func pixelsOK() bool { return gfx != gfxNone && !th.Mono }
+
+// render is the mode frames are actually drawn in: a saved pixels mode
+// becomes color on a terminal without graphics.
+func (y *ytModel) render() ytMode {
+	if y.mode == ytPixels && !pixelsOK() {
+		return ytColor
+	}
+	return y.mode
+}
+
+// painted reports whether the screen is text the painter can diff; pixel
+// frames and the loading and no-video messages go through Bubble Tea.
+func (y *ytModel) painted() bool {
+	return y.active && y.render() != ytPixels && (y.scene != nil || (y.hasVideo && !y.loading))
+}
+
+func parseYTMode(s string) (ytMode, bool) {
+	for i, n := range ytModeNames {
+		if n == s {
+			return ytMode(i), true
+		}
+	}
+	return ytMono, false
+}
+
+// cellPixels is how many frame pixels one terminal cell covers.
+func (y *ytModel) cellPixels() (int, int) {
+	switch y.render() {
+	case ytHalf:
+		return 1, 2
+	case ytBraille:
+		return 2, 4
+	case ytPixels:
+		return gfxCellPixels(y.cols, y.rows)
+	}
+	return 1, 1
+}
+
+// pixelDims is the frame size ffmpeg and the scenes draw at.
+func (y *ytModel) pixelDims() (int, int) {
+	px, py := y.cellPixels()
+	return y.cols * px, y.rows * py
+}
+
+// pixelAspect is a frame pixel's height over its width, taking cells as
+// twice as tall as wide.
+func (y *ytModel) pixelAspect() float64 {
+	px, py := y.cellPixels()
+	return 2 * float64(px) / float64(py)
+}
+
+// toASCII converts one rgb24 frame of pixelDims to text in the current
+// mode, after the contrast/gamma curve. The mono theme drops color from
+// every mode but keeps the resolution.
+func (y *ytModel) toASCII(rgb []byte, w, h int) string {
+	color := !th.Mono && !noColor
+	mode := y.render()
+	look := currentLook()
+	look.apply(rgb)
+	if mode == ytPixels {
+		return gfxBlock(gfxImage(gfxVideoID, rgb, w, h, y.cols, y.rows), y.cols, y.rows) + "\n"
+	}
+	return gfxClear(gfxVideoID) + asciiFrame(mode, rgb, w, h, color, look.dither)
+}
+
+func asciiFrame(mode ytMode, rgb []byte, w, h int, color bool, d ditherKind) string {
+	switch mode {
+	case ytHalf:
+		if color {
+			return halfBlockANSI(rgb, w, h)
+		}
+		return halfBlockMono(rgb, w, h)
+	case ytBraille:
+		return brailleFrame(rgb, w, h, color)
+	case ytColor:
+		if color && penProfile() == termenv.ANSI {
+			return color16ASCII(rgb, w, h, d)
+		}
+		if color {
+			return fastColorASCII(rgb, w, h)
+		}
+	}
+	if d != ditherNone {
+		return monoDithered(rgb, w, h, d)
+	}
+	return fastMonoASCII(rgb, w, h)
+}
+
+func luma(r, g, b byte) int { return (54*int(r) + 183*int(g) + 19*int(b)) >> 8 }
+
+func appendRGB(buf []byte, r, g, b byte) []byte {
+	buf = strconv.AppendInt(buf, int64(r), 10)
+	buf = append(buf, ';')
+	buf = strconv.AppendInt(buf, int64(g), 10)
+	buf = append(buf, ';')
+	return strconv.AppendInt(buf, int64(b), 10)
+}
+
+// halfBlockANSI draws two pixels per cell with an upper half block: the
+// top pixel as foreground, the bottom one as background.
+func halfBlockANSI(rgb []byte, w, h int) string {
+	buf := make([]byte, 0, w*(h/2)*24)
+	for y := 0; y+1 < h; y += 2 {
+		top := rgb[y*w*3:]
+		bot := rgb[(y+1)*w*3:]
+		lastFg, lastBg := penNone, penNone
+		for x := 0; x < w; x++ {
+			t, b := top[x*3:x*3+3], bot[x*3:x*3+3]
+			pf, pb := penFor(t[0], t[1], t[2]), penFor(b[0], b[1], b[2])
+			switch {
+			case pf != lastFg && pb != lastBg:
+				buf = append(buf, "\x1b["...)
+				buf = appendPen(buf, pf, false)
+				buf = append(buf, ';')
+				buf = appendPen(buf, pb, true)
+				buf = append(buf, 'm')
+			case pf != lastFg:
+				buf = append(buf, "\x1b["...)
+				buf = appendPen(buf, pf, false)
+				buf = append(buf, 'm')
+			case pb != lastBg:
+				buf = append(buf, "\x1b["...)
+				buf = appendPen(buf, pb, true)
+				buf = append(buf, 'm')
+			}
+			lastFg, lastBg = pf, pb
+			buf = append(buf, "▀"...)
+		}
+		buf = append(buf, "\x1b[0m\n"...)
+	}
+	return string(buf)
+}
+
+// litThreshold is the frame's mean luma, so dark scenes still show
+// something when each pixel can only be on or off.
+func litThreshold(rgb []byte, w, h int) int {
+	sum := 0
+	for i := 0; i < w*h*3; i += 3 {
+		sum += luma(rgb[i], rgb[i+1], rgb[i+2])
+	}
+	return max(24, sum/max(1, w*h))
+}
+
+var halfBlocks = [4]string{" ", "▀", "▄", "█"}
+
+// halfBlockMono is the colorless half-block mode: each half of the cell
+// is lit or not.
+func halfBlockMono(rgb []byte, w, h int) string {
+	thresh := litThreshold(rgb, w, h)
+	buf := make([]byte, 0, w*(h/2)*3+h/2)
+	for y := 0; y+1 < h; y += 2 {
+		for x := 0; x < w; x++ {
+			t := rgb[(y*w+x)*3:]
+			b := rgb[((y+1)*w+x)*3:]
+			i := 0
+			if luma(t[0], t[1], t[2]) > thresh {
+				i |= 1
+			}
+			if luma(b[0], b[1], b[2]) > thresh {
+				i |= 2
+			}
+			buf = append(buf, halfBlocks[i]...)
+		}
+		buf = append(buf, '\n')
+	}
+	return string(buf)
+}
+
+// brailleFrame draws 2x4 pixels per cell as braille dots, lit where the
+// pixel is brighter than the frame's average. With color on, each cell
+// takes the mean color of its lit dots.
+func brailleFrame(rgb []byte, w, h int, color bool) string {
+	thresh := litThreshold(rgb, w, h)
+
+	cols, rows := w/2, h/4
+	buf := make([]byte, 0, cols*rows*12)
+	for cy := 0; cy < rows; cy++ {
+		last := penNone
+		for cx := 0; cx < cols; cx++ {
+			r := rune(0x2800)
+			var cr, cg, cb, n int
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					p := rgb[((cy*4+dy)*w+cx*2+dx)*3:]
+					if luma(p[0], p[1], p[2]) > thresh {
+						r |= brailleBits[dy][dx]
+						cr, cg, cb, n = cr+int(p[0]), cg+int(p[1]), cb+int(p[2]), n+1
+					}
+				}
+			}
+			// blank cells show no color, so they keep the last one
+			if color && n > 0 {
+				if p := penFor(byte(cr/n), byte(cg/n), byte(cb/n)); p != last {
+					buf = append(buf, "\x1b["...)
+					buf = appendPen(buf, p, false)
+					buf = append(buf, 'm')
+					last = p
+				}
+			}
+			buf = append(buf, string(r)...)
+		}
+		if color {
+			buf = append(buf, "\x1b[0m"...)
+		}
+		buf = append(buf, '\n')
+	}
+	return string(buf)
+}
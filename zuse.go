@@ -1,13 +1,11 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -176,9 +174,9 @@ type ssePacket struct {
 	Args      []string `json:"Args"`
 }
 
-func initialIRCModel() *ircModel {
+func ircDelegates() (darkDel, brightDel list.DefaultDelegate) {
 	// darker selection (when LEFT pane is not focused)
-	darkDel := list.NewDefaultDelegate()
+	darkDel = list.NewDefaultDelegate()
 	darkDel.ShowDescription = true
 	darkDel.Styles.NormalTitle = stylePink
 	darkDel.Styles.NormalDesc = styleDim
@@ -186,12 +184,17 @@ func initialIRCModel() *ircModel {
 	darkDel.Styles.SelectedDesc = styleDarkSel
 
 	// original pink selection (when LEFT pane IS focused)
-	brightDel := list.NewDefaultDelegate()
+	brightDel = list.NewDefaultDelegate()
 	brightDel.ShowDescription = true
 	brightDel.Styles.NormalTitle = stylePink
 	brightDel.Styles.NormalDesc = styleDim
 	brightDel.Styles.SelectedTitle = styleSel
 	brightDel.Styles.SelectedDesc = styleSel
+	return darkDel, brightDel
+}
+
+func initialIRCModel() *ircModel {
+	darkDel, brightDel := ircDelegates()
 
 	l := list.New([]list.Item{addServerItem{}}, darkDel, 20, 10) // start with RIGHT pane focused
 
@@ -303,15 +306,15 @@ func (m *ircModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "esc":
+		switch keys.action(scrIRC, msg.String()) {
+		case "quit":
 			return m, tea.Quit
-		case "left":
+		case "focus-servers":
 			m.focus = paneServers
 			m.blurRight()
 			m.applyListFocus()
 			return m, nil
-		case "right":
+		case "focus-chat":
 			m.focus = paneRight
 			m.focusRight()
 			m.applyListFocus()
@@ -431,8 +434,8 @@ func listLen(l list.Model) int { return len(l.Items()) }
 /* LEFT PANE */
 
 func (m *ircModel) updateServersPane(key tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch key.String() {
-	case "enter":
+	switch keys.action(scrIRCServers, key.String()) {
+	case "open":
 		if listLen(m.serverList) == 0 {
 			return m, nil
 		}
@@ -474,14 +477,14 @@ func (m *ircModel) updateServersPane(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-	case "a":
+	case "add":
 		m.mode = modeForm
 		m.focus = paneRight
 		m.clearForm()
 		m.focusRight()
 		return m, nil
 
-	case "d":
+	case "delete":
 		if listLen(m.serverList) == 0 {
 			return m, nil
 		}
@@ -1235,49 +1238,23 @@ func (m *ircModel) stopPreviewForServer(id serverID) {
 }
 
 func (m *ircModel) runSSEPreview(ctx context.Context, id serverID, ch, url string) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		m.push(ircChanLineMsg{id: id, channel: ch, line: styleDim.Render("[preview] build request failed: " + err.Error())})
-		return
-	}
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
-	client := &http.Client{Timeout: 0}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		m.push(ircChanLineMsg{id: id, channel: ch, line: styleDim.Render("[preview] connect failed: " + err.Error())})
-		return
-	}
-	defer resp.Body.Close()
-
-	sc := bufio.NewScanner(resp.Body)
-	// increase buffer for long name lists
-	buf := make([]byte, 0, 64*1024)
-	sc.Buffer(buf, 1024*1024)
-
-	for sc.Scan() {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
-		line := sc.Text()
-		if !strings.HasPrefix(line, "data: ") {
-			continue
-		}
-		payload := strings.TrimPrefix(line, "data: ")
+	c := newSSEClient("preview "+ch, url, func(ev sseEvent) {
 		var items []ssePacket
-		if err := json.Unmarshal([]byte(payload), &items); err != nil {
-			continue
+		if err := json.Unmarshal([]byte(ev.Data), &items); err != nil {
+			return
 		}
 		for _, ev := range items {
 			for _, msg := range m.translateSSEToMsgs(id, ch, ev) {
 				m.push(msg)
 			}
 		}
+	})
+	c.onState = func(st sseState, err error) {
+		if st == sseRetrying && err != nil && ctx.Err() == nil {
+			m.push(ircChanLineMsg{id: id, channel: ch, line: styleDim.Render("[preview] " + err.Error() + ", retrying")})
+		}
 	}
-	// ignore scanner errors on cancel/close
+	c.Run(ctx)
 }
 
 /* Translate SSE packets into ircChanLineMsg slice */
@@ -1387,10 +1364,15 @@ func (m *ircModel) viewForm() string {
 			b.WriteString(label + "\n" + m.formInputs[i].View() + "\n\n")
 		}
 	}
-	b.WriteString(styleDim.Render("↑/↓ fields · Enter submit · ←/→ panes"))
+	b.WriteString(styleDim.Render("↑/↓ fields · Enter submit · " + paneKeys() + " panes"))
 	return b.String()
 }
 
+// paneKeys is the hint for moving between the servers and chat panes.
+func paneKeys() string {
+	return keys.label(scrIRC, "focus-servers") + "/" + keys.label(scrIRC, "focus-chat")
+}
+
 func (m *ircModel) viewChat() string {
 	var header strings.Builder
 	title := "Chat"
@@ -1406,7 +1388,7 @@ func (m *ircModel) viewChat() string {
 		title = fmt.Sprintf("%s %s (%s) %s", stat, s.name, s.nick, chanLabel)
 	}
 	header.WriteString(stylePinkB.Render(title) + "\n")
-	header.WriteString(titleStyle.Render("↑/↓ scroll · ←/→ panes") + "\n")
+	header.WriteString(titleStyle.Render("↑/↓ scroll · "+paneKeys()+" panes") + "\n")
 
 	div := stylePink.Render(strings.Repeat("─", m.chatVP.Width))
 